@@ -0,0 +1,128 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// accessSet is the set of accounts and storage slots touched by a transaction.
+type accessSet map[common.Address]map[common.Hash]struct{}
+
+// add records an access to the account and, if slot is non-nil, to one of its
+// storage slots.
+func (s accessSet) add(addr common.Address, slot *common.Hash) {
+	slots, ok := s[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		s[addr] = slots
+	}
+	if slot != nil {
+		slots[*slot] = struct{}{}
+	}
+}
+
+// accessTracer collects the accounts and storage slots accessed by each
+// transaction of a block during execution. System calls made outside of
+// transactions are not recorded.
+type accessTracer struct {
+	txs     []accessSet // Access sets, indexed by transaction position
+	current accessSet   // Access set of the running transaction, nil outside of one
+}
+
+func newAccessTracer() *accessTracer {
+	return &accessTracer{}
+}
+
+// hooks returns the tracing hooks feeding the tracer.
+func (t *accessTracer) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: t.onTxStart,
+		OnTxEnd:   t.onTxEnd,
+		OnEnter:   t.onEnter,
+		OnOpcode:  t.onOpcode,
+	}
+}
+
+func (t *accessTracer) onTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.current = make(accessSet)
+	t.txs = append(t.txs, t.current)
+	t.current.add(from, nil)
+}
+
+func (t *accessTracer) onTxEnd(receipt *types.Receipt, err error) {
+	t.current = nil
+}
+
+func (t *accessTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.current == nil {
+		return
+	}
+	t.current.add(to, nil)
+}
+
+func (t *accessTracer) onOpcode(pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.current == nil || err != nil {
+		return
+	}
+	stack := scope.StackData()
+	if len(stack) == 0 {
+		return
+	}
+	switch op := vm.OpCode(opcode); op {
+	case vm.SLOAD, vm.SSTORE:
+		slot := common.Hash(stack[len(stack)-1].Bytes32())
+		t.current.add(scope.Address(), &slot)
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH, vm.SELFDESTRUCT:
+		t.current.add(common.Address(stack[len(stack)-1].Bytes20()), nil)
+	}
+}
+
+// accessed returns the access set of the transaction at the given index, or
+// nil if the tracer did not see it execute.
+func (t *accessTracer) accessed(tx int) accessSet {
+	if tx < 0 || tx >= len(t.txs) {
+		return nil
+	}
+	return t.txs[tx]
+}
+
+// addresses returns the distinct accounts accessed by all transactions of the
+// block, sorted in ascending order.
+func (t *accessTracer) addresses() []common.Address {
+	seen := make(map[common.Address]struct{})
+	for _, set := range t.txs {
+		for addr := range set {
+			seen[addr] = struct{}{}
+		}
+	}
+	addrs := make([]common.Address, 0, len(seen))
+	for addr := range seen {
+		addrs = append(addrs, addr)
+	}
+	slices.SortFunc(addrs, func(a, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return addrs
+}
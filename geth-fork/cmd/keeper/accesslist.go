@@ -0,0 +1,135 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// accessKey identifies an account, or one of its storage slots, accessed or
+// declared by a transaction.
+type accessKey struct {
+	Tx      int
+	Address common.Address
+	Slot    *common.Hash // nil for the account itself
+}
+
+func (k accessKey) String() string {
+	if k.Slot == nil {
+		return fmt.Sprintf("tx %d account %s", k.Tx, k.Address.Hex())
+	}
+	return fmt.Sprintf("tx %d account %s slot %s", k.Tx, k.Address.Hex(), k.Slot.Hex())
+}
+
+// accessListReport is the outcome of comparing the EIP-2930 access lists
+// declared by the transactions of a block against the keys they accessed
+// during execution and the keys covered by the witness.
+type accessListReport struct {
+	Undeclared []accessKey // Accessed during execution but not declared (informational)
+	Missing    []accessKey // Declared but not resolvable from the witness (witness gap)
+}
+
+// print writes the findings of the report, one per line.
+func (r *accessListReport) print(w io.Writer) {
+	for _, key := range r.Missing {
+		fmt.Fprintf(w, "access list: %s declared but missing from witness\n", key)
+	}
+	for _, key := range r.Undeclared {
+		fmt.Fprintf(w, "access list: %s accessed but not declared\n", key)
+	}
+}
+
+// compareAccessLists checks the access list of every transaction in the block.
+// Addresses that are warm by default (sender, recipient, coinbase and active
+// precompiles) are not reported as undeclared.
+func compareAccessLists(config *params.ChainConfig, block *types.Block, witness *stateless.Witness, tracer *accessTracer) (*accessListReport, error) {
+	state, err := newWitnessState(witness)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		report = new(accessListReport)
+		rules  = config.Rules(block.Number(), block.Difficulty().Sign() == 0, block.Time())
+		warm   = make(map[common.Address]bool)
+	)
+	for _, addr := range vm.ActivePrecompiles(rules) {
+		warm[addr] = true
+	}
+	for i, tx := range block.Transactions() {
+		declared := make(accessSet)
+		for _, tuple := range tx.AccessList() {
+			declared.add(tuple.Address, nil)
+			if _, err := state.account(tuple.Address); err != nil {
+				report.Missing = append(report.Missing, accessKey{Tx: i, Address: tuple.Address})
+			}
+			for _, slot := range tuple.StorageKeys {
+				declared.add(tuple.Address, &slot)
+				if _, err := state.storage(tuple.Address, slot); err != nil {
+					report.Missing = append(report.Missing, accessKey{Tx: i, Address: tuple.Address, Slot: &slot})
+				}
+			}
+		}
+		for addr, slots := range tracer.accessed(i) {
+			if _, ok := declared[addr]; !ok && !warm[addr] && addr != block.Coinbase() && !isTxEndpoint(tx, addr, config, block) {
+				report.Undeclared = append(report.Undeclared, accessKey{Tx: i, Address: addr})
+			}
+			for slot := range slots {
+				if _, ok := declared[addr][slot]; !ok {
+					report.Undeclared = append(report.Undeclared, accessKey{Tx: i, Address: addr, Slot: &slot})
+				}
+			}
+		}
+	}
+	slices.SortFunc(report.Undeclared, compareAccessKeys)
+	return report, nil
+}
+
+// isTxEndpoint reports whether addr is the sender or recipient of tx.
+func isTxEndpoint(tx *types.Transaction, addr common.Address, config *params.ChainConfig, block *types.Block) bool {
+	if to := tx.To(); to != nil && *to == addr {
+		return true
+	}
+	signer := types.MakeSigner(config, block.Number(), block.Time())
+	from, err := types.Sender(signer, tx)
+	return err == nil && from == addr
+}
+
+func compareAccessKeys(a, b accessKey) int {
+	if a.Tx != b.Tx {
+		return a.Tx - b.Tx
+	}
+	if c := a.Address.Cmp(b.Address); c != 0 {
+		return c
+	}
+	switch {
+	case a.Slot == nil && b.Slot == nil:
+		return 0
+	case a.Slot == nil:
+		return -1
+	case b.Slot == nil:
+		return 1
+	}
+	return a.Slot.Cmp(*b.Slot)
+}
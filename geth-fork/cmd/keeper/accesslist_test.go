@@ -0,0 +1,92 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestCompareAccessLists tests that undeclared accesses and declared keys the
+// witness cannot resolve are both reported.
+func TestCompareAccessLists(t *testing.T) {
+	var (
+		declared   = common.HexToAddress("0x1000")
+		undeclared = common.HexToAddress("0x2000")
+		slotA      = common.HexToHash("0x01")
+		slotB      = common.HexToHash("0x02")
+	)
+	tx := types.NewTx(&types.AccessListTx{
+		ChainID: big.NewInt(1),
+		Gas:     21000,
+		To:      &declared,
+		AccessList: types.AccessList{
+			{Address: declared, StorageKeys: []common.Hash{slotA}},
+		},
+	})
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}, &types.Body{Transactions: types.Transactions{tx}}, nil, trie.NewStackTrie(nil))
+
+	tracer := newAccessTracer()
+	tracer.txs = []accessSet{{}}
+	tracer.txs[0].add(declared, &slotA)
+	tracer.txs[0].add(declared, &slotB)
+	tracer.txs[0].add(undeclared, nil)
+
+	// An empty pre-state proves the absence of every declared key.
+	witness := &stateless.Witness{Headers: []*types.Header{{Number: big.NewInt(0), Root: types.EmptyRootHash}}}
+	report, err := compareAccessLists(params.MainnetChainConfig, block, witness, tracer)
+	if err != nil {
+		t.Fatalf("compareAccessLists failed: %v", err)
+	}
+	if len(report.Missing) != 0 {
+		t.Errorf("unexpected witness gaps: %v", report.Missing)
+	}
+	want := []string{
+		accessKey{Tx: 0, Address: declared, Slot: &slotB}.String(),
+		accessKey{Tx: 0, Address: undeclared}.String(),
+	}
+	if len(report.Undeclared) != len(want) {
+		t.Fatalf("undeclared accesses = %v, want %v", report.Undeclared, want)
+	}
+	for i, key := range report.Undeclared {
+		if key.String() != want[i] {
+			t.Errorf("undeclared access %d = %s, want %s", i, key, want[i])
+		}
+	}
+
+	addrs := tracer.addresses()
+	if len(addrs) != 2 || addrs[0] != declared || addrs[1] != undeclared {
+		t.Errorf("accessed addresses = %v, want [%v %v]", addrs, declared, undeclared)
+	}
+
+	// A pre-state root without any backing nodes leaves every declared key
+	// unresolvable.
+	witness = &stateless.Witness{Headers: []*types.Header{{Number: big.NewInt(0), Root: common.HexToHash("0xdead")}}}
+	report, err = compareAccessLists(params.MainnetChainConfig, block, witness, tracer)
+	if err != nil {
+		t.Fatalf("compareAccessLists failed: %v", err)
+	}
+	if len(report.Missing) != 2 {
+		t.Errorf("witness gaps = %v, want account and slot", report.Missing)
+	}
+}
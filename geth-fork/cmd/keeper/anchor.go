@@ -0,0 +1,49 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VerifyAnchored reports whether the block hash is the leaf at the given index
+// of the Merkle tree with the given anchored root. The proof lists the sibling
+// hashes from the leaf up to the root. Following the anchoring contract, leaves
+// are used as is and every parent is the Keccak256 of its left and right child
+// concatenated, with the bits of the index, from least significant upward,
+// telling whether the node at each level is the left (0) or right (1) child.
+// The contract does not separate leaf and parent nodes as the Merkle trees of
+// the crypto package do, so the proof is checked here.
+func VerifyAnchored(root common.Hash, blockHash common.Hash, proof [][]byte, index int) bool {
+	if index < 0 || len(proof) < 64 && index>>len(proof) != 0 {
+		return false
+	}
+	node := blockHash
+	for _, sibling := range proof {
+		if len(sibling) != common.HashLength {
+			return false
+		}
+		if index&1 == 0 {
+			node = crypto.Keccak256Hash(node[:], sibling)
+		} else {
+			node = crypto.Keccak256Hash(sibling, node[:])
+		}
+		index >>= 1
+	}
+	return node == root
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestVerifyAnchored tests inclusion proofs against a four leaf tree.
+func TestVerifyAnchored(t *testing.T) {
+	leaves := []common.Hash{{0x01}, {0x02}, {0x03}, {0x04}}
+	left := crypto.Keccak256Hash(leaves[0][:], leaves[1][:])
+	right := crypto.Keccak256Hash(leaves[2][:], leaves[3][:])
+	root := crypto.Keccak256Hash(left[:], right[:])
+
+	proofs := [][][]byte{
+		{leaves[1][:], right[:]},
+		{leaves[0][:], right[:]},
+		{leaves[3][:], left[:]},
+		{leaves[2][:], left[:]},
+	}
+	for i, proof := range proofs {
+		if !VerifyAnchored(root, leaves[i], proof, i) {
+			t.Errorf("leaf %d: valid proof rejected", i)
+		}
+	}
+	// Proofs must be rejected for the wrong leaf, position, root or shape.
+	if VerifyAnchored(root, leaves[1], proofs[0], 0) {
+		t.Error("proof accepted for the wrong leaf")
+	}
+	if VerifyAnchored(root, leaves[0], proofs[0], 1) {
+		t.Error("proof accepted at the wrong index")
+	}
+	if VerifyAnchored(root, leaves[0], proofs[0], 4) {
+		t.Error("proof accepted for an index outside the tree")
+	}
+	if VerifyAnchored(left, leaves[0], proofs[0], 0) {
+		t.Error("proof accepted against the wrong root")
+	}
+	if VerifyAnchored(root, leaves[0], [][]byte{leaves[1][:31], right[:]}, 0) {
+		t.Error("proof accepted with a truncated sibling")
+	}
+	if !VerifyAnchored(leaves[0], leaves[0], nil, 0) {
+		t.Error("single leaf tree rejected")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// validationReceipt is a compact, signed attestation that a block was validated
+// and produced the given roots. Unlike the full result, it only carries what is
+// needed to audit the claim later and to reference it on-chain.
+type validationReceipt struct {
+	ChainID     uint64         `json:"chainId"`
+	Number      uint64         `json:"number"`
+	Hash        common.Hash    `json:"hash"`
+	StateRoot   common.Hash    `json:"stateRoot"`
+	ReceiptRoot common.Hash    `json:"receiptRoot"`
+	Time        uint64         `json:"time"`
+	Version     string         `json:"version"`
+	Signer      common.Address `json:"signer"`
+	Signature   hexutil.Bytes  `json:"signature"`
+}
+
+// sigHash returns the hash signed by the keeper: the Keccak256 of the RLP list
+// of all attested fields, in declaration order.
+func (r *validationReceipt) sigHash() common.Hash {
+	blob, _ := rlp.EncodeToBytes([]any{r.ChainID, r.Number, r.Hash, r.StateRoot, r.ReceiptRoot, r.Time, r.Version})
+	return crypto.Keccak256Hash(blob)
+}
+
+// newValidationReceipt creates and signs the receipt of a successful validation.
+func newValidationReceipt(result *Result, time uint64, key *ecdsa.PrivateKey) (*validationReceipt, error) {
+	if !result.Valid {
+		return nil, errors.New("block was not validated")
+	}
+	receipt := &validationReceipt{
+		ChainID:     result.ChainID,
+		Number:      result.Number,
+		Hash:        result.Hash,
+		StateRoot:   result.StateRoot,
+		ReceiptRoot: result.ReceiptRoot,
+		Time:        time,
+		Version:     keeperVersion(),
+		Signer:      crypto.PubkeyToAddress(key.PublicKey),
+	}
+	sig, err := crypto.Sign(receipt.sigHash().Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Signature = sig
+	return receipt, nil
+}
+
+// verify checks that the receipt was signed by its declared signer.
+func (r *validationReceipt) verify() error {
+	pub, err := crypto.SigToPub(r.sigHash().Bytes(), r.Signature)
+	if err != nil {
+		return err
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != r.Signer {
+		return fmt.Errorf("receipt signed by %s, not by declared signer %s", signer.Hex(), r.Signer.Hex())
+	}
+	return nil
+}
+
+// writeValidationReceipt stores the receipt as JSON at the given path.
+func writeValidationReceipt(path string, receipt *validationReceipt) error {
+	blob, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(blob, '\n'), 0644)
+}
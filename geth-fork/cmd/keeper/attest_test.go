@@ -0,0 +1,65 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestValidationReceipt tests that receipts are signed over all attested fields
+// and survive a round trip through their file.
+func TestValidationReceipt(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	result := &Result{ChainID: 1, Number: 100, Hash: common.Hash{0x01}, StateRoot: common.Hash{0x02}, ReceiptRoot: common.Hash{0x03}, Valid: true}
+
+	if _, err := newValidationReceipt(&Result{}, 0, key); err == nil {
+		t.Fatal("receipt created for a failed validation")
+	}
+	receipt, err := newValidationReceipt(result, 1700000000, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "receipt.json")
+	if err := writeValidationReceipt(path, receipt); err != nil {
+		t.Fatal(err)
+	}
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored validationReceipt
+	if err := json.Unmarshal(blob, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := stored.verify(); err != nil {
+		t.Fatalf("stored receipt failed to verify: %v", err)
+	}
+	if stored.Signer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("signer = %s, want %s", stored.Signer.Hex(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+	}
+	// Tampering with any attested field invalidates the signature.
+	stored.StateRoot = common.Hash{0xff}
+	if err := stored.verify(); err == nil {
+		t.Error("tampered receipt verified")
+	}
+}
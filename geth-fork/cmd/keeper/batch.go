@@ -0,0 +1,494 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// batchPrefixSize is the size of the big-endian length prefix preceding every
+// payload in a batch input.
+const batchPrefixSize = 4
+
+// splitBatch splits a batch input into its individual payloads. Each payload
+// is prefixed by its length as a 4 byte big-endian integer.
+func splitBatch(input []byte) ([][]byte, error) {
+	var payloads [][]byte
+	for offset := 0; offset < len(input); {
+		if len(input)-offset < batchPrefixSize {
+			return nil, fmt.Errorf("truncated length prefix at offset %d", offset)
+		}
+		size := int(binary.BigEndian.Uint32(input[offset:]))
+		offset += batchPrefixSize
+		if len(input)-offset < size {
+			return nil, fmt.Errorf("payload %d truncated at offset %d (want %d bytes, have %d)", len(payloads), offset, size, len(input)-offset)
+		}
+		payloads = append(payloads, input[offset:offset+size])
+		offset += size
+	}
+	return payloads, nil
+}
+
+// batchConfig holds the settings of a batch run.
+type batchConfig struct {
+	print  func(*Result) error // Function printing every result to stdout, if set
+	outdir string              // Directory receiving each result as soon as it completes
+	append string              // File every new result is appended to as a line of JSON
+	sample float64             // Fraction of payloads to validate, 0 validates all of them
+	seed   string              // Explicit sampling seed, derived from the payloads if empty
+
+	errorsOnly bool // Print only the results of payloads that failed
+
+	sinceBlock uint64 // Block number at or below which payloads are skipped, 0 skips none
+
+	checkpoint string // File recording the last processed payload, to resume after it
+
+	reverse    bool // Validate from the last payload to the first
+	continuity bool // Require consecutive payloads to be linked by parent hash
+
+	deferGas    uint64 // Gas usage above which blocks are deferred instead of validated
+	deferOutput string // File every deferred block is appended to as a line of JSON
+
+	prefetch int  // Number of payloads to decode ahead of validation, 0 disables prefetching
+	parallel int  // Number of payloads to validate concurrently, 0 or 1 validates them one by one
+	twoPhase bool // Decode all payloads before executing any, and none if one fails to decode
+
+	metrics *metricsWriter // Metrics file updated after every payload, if set
+
+	memoryBudget uint64 // Memory in bytes a single payload may use, 0 for no limit
+
+	collect        bool // Collect garbage after every payload, for runs with the collector disabled
+	aggressiveFree bool // Drop every payload and return its memory to the OS once processed
+
+	interrupted <-chan struct{} // Closed to stop the run before its next payload
+}
+
+// checkContinuity verifies that the block of the child result directly extends
+// the block of the parent result. Results of payloads that could not be decoded
+// carry no block hashes and are not checked, their failure is already reported.
+func checkContinuity(parent, child *Result) error {
+	if parent.Hash == (common.Hash{}) || child.Hash == (common.Hash{}) {
+		return nil
+	}
+	if child.ParentHash != parent.Hash {
+		return fmt.Errorf("block %d parent hash %x does not match block %d hash %x", child.Number, child.ParentHash, parent.Number, parent.Hash)
+	}
+	return nil
+}
+
+// runBatch validates every payload of a batch input in order, continuing past
+// individual failures, and returns the exit code of the whole run.
+func runBatch(input []byte, config *batchConfig) int {
+	payloads, err := splitBatch(input)
+	if err != nil {
+		logger.Error("Invalid batch input", "err", err)
+		return ExitInvalidInput
+	}
+	return runPayloads(payloads, nil, config)
+}
+
+// runPayloads validates the given payloads in order, continuing past individual
+// failures, and returns the exit code of the whole run. If names are given,
+// payloads are reported by name, and each result records the name of its
+// payload.
+//
+// If an output directory is set, the result of each payload is written to its
+// own file in that directory as soon as it completes. Payloads whose result
+// file already exists are not validated again, which allows an interrupted run
+// to resume where it left off.
+//
+// With a checkpoint file, the position of every processed payload is recorded
+// in it along with the running counts, and a restarted run continues after the
+// recorded payload, without needing a result file per payload.
+//
+// Payloads whose block number is at or below the since-block watermark are
+// skipped without being validated, to resume a forward backfill.
+//
+// Blocks using more gas than the deferral limit are not validated but recorded
+// in the deferred output, to be handled separately; they do not count as
+// failures.
+//
+// The memory of every payload is collected once it has been processed if the
+// garbage collector is disabled, or on request also returned to the OS.
+//
+// With a memory budget, every payload is processed under a memory guard, and
+// fails with ExitMemoryExceeded if its memory use exceeds the budget, without
+// affecting the rest of the batch.
+//
+// With parallelism, up to that many payloads are validated concurrently, each
+// decoded and executed by its own worker, while results are still reported in
+// order, exactly as if the payloads were validated one by one.
+//
+// In two-phase mode, all payloads are decoded and structurally validated up
+// front, and none is executed unless all of them pass.
+//
+// Once the interrupted channel is closed, the run stops before the next payload
+// and returns ExitInterrupted. The payload in progress is completed and
+// recorded first, so results and checkpoint are left consistent for a restart.
+//
+// Payloads are expected in ascending block order. In reverse mode they are
+// validated from the last to the first, allowing a backward audit from a
+// trusted tip; with chain continuity enabled, every block is then checked to
+// be the parent of the previously validated one.
+func runPayloads(payloads [][]byte, names []string, config *batchConfig) int {
+	label := func(i int) string {
+		if names != nil {
+			return names[i]
+		}
+		return strconv.Itoa(i)
+	}
+	outdir := config.outdir
+	if outdir != "" {
+		if err := os.MkdirAll(outdir, 0755); err != nil {
+			logger.Error("Failed to create batch output directory", "dir", outdir, "err", err)
+			return ExitInvalidInput
+		}
+	}
+	var seed common.Hash
+	if config.sample > 0 {
+		seed = deriveSeed(payloads, config.seed)
+		logger.Info("Sampling batch", "rate", config.sample, "payloads", len(payloads), "seed", seed)
+	}
+	var (
+		order            []int
+		skipped, already int
+	)
+	for n := range payloads {
+		i := n
+		if config.reverse {
+			i = len(payloads) - 1 - n
+		}
+		if config.sample > 0 && !sampled(seed, i, config.sample) {
+			skipped++
+			continue
+		}
+		if config.sinceBlock > 0 {
+			// Payloads whose header cannot be decoded are kept, their
+			// validation reports the failure.
+			if _, header, err := peekHeader(payloads[i]); err == nil && header.Number.Uint64() <= config.sinceBlock {
+				already++
+				continue
+			}
+		}
+		order = append(order, i)
+	}
+	var progress *checkpoint
+	if config.checkpoint != "" {
+		var err error
+		if progress, err = readCheckpoint(config.checkpoint); err != nil {
+			logger.Error("Failed to read checkpoint", "path", config.checkpoint, "err", err)
+			return ExitInvalidInput
+		}
+		if progress != nil {
+			remaining, err := resumeOrder(order, payloads, progress)
+			if err != nil {
+				logger.Error("Checkpoint does not match the batch", "path", config.checkpoint, "err", err)
+				return ExitInvalidInput
+			}
+			logger.Info("Resuming batch from checkpoint", "path", config.checkpoint, "completed", len(order)-len(remaining), "remaining", len(remaining))
+			order = remaining
+		}
+	}
+	// settled reports whether the payload at the given position is not to be
+	// validated, as its result is resumed from the output directory or it is
+	// deferred, mirroring the checks of the loop below.
+	settled := func(i int) bool {
+		if outdir != "" {
+			if _, err := readResultFile(outdir, i); err == nil {
+				return true
+			}
+		}
+		if config.deferGas > 0 {
+			if _, header, err := peekHeader(payloads[i]); err == nil && header.GasUsed > config.deferGas {
+				return true
+			}
+		}
+		return false
+	}
+	var decoded map[int]decodedPayload
+	if config.twoPhase {
+		var failures int
+		if decoded, failures = decodeAll(payloads, order, settled, label); failures > 0 {
+			logger.Error("Batch payloads failed to decode, none executed", "failed", failures, "payloads", len(order))
+			return ExitBatchFailed
+		}
+	}
+	var prefetch *prefetcher
+	switch {
+	case config.parallel > 1:
+		prefetch = newPrefetcher(order, config.parallel, func(i int) decodedPayload {
+			// Resumed and deferred payloads are discarded by the loop below.
+			if settled(i) {
+				return decodedPayload{}
+			}
+			return decodedPayload{result: process(payloads[i])}
+		})
+		defer prefetch.close()
+	case config.prefetch > 0:
+		prefetch = newPrefetcher(order, config.prefetch, func(i int) decodedPayload {
+			payload, result := decodePayload(payloads[i])
+			return decodedPayload{payload, result}
+		})
+		defer prefetch.close()
+	}
+	var (
+		valid, failed, deferred, resumed, broken int
+		previous                                 *Result
+	)
+	if progress != nil {
+		valid, failed, deferred, broken = progress.Valid, progress.Failed, progress.Deferred, progress.Unlinked
+		previous = progress.Last
+	}
+	for n, i := range order {
+		select {
+		case <-config.interrupted:
+			logger.Warn("Batch interrupted", "valid", valid, "failed", failed, "deferred", deferred, "resumed", resumed, "remaining", len(order)-n)
+			return ExitInterrupted
+		default:
+		}
+		payload := payloads[i]
+		var (
+			result        *Result
+			resumedResult bool
+			discarded     bool
+		)
+		if outdir != "" {
+			var err error
+			if result, err = readResultFile(outdir, i); err == nil {
+				resumed++
+				resumedResult = true
+			} else if !errors.Is(err, os.ErrNotExist) {
+				logger.Warn("Discarding unreadable result", "payload", label(i), "err", err)
+			}
+		}
+		if result == nil && config.deferGas > 0 {
+			if chainID, header, err := peekHeader(payload); err == nil && header.GasUsed > config.deferGas {
+				result = deferredResult(chainID, header)
+				if err := appendResult(config.deferOutput, result); err != nil {
+					logger.Error("Failed to record deferred block", "payload", label(i), "err", err)
+					return ExitBatchFailed
+				}
+			}
+		}
+		var guard *memoryGuard
+		if result == nil && config.memoryBudget > 0 {
+			guard = startMemoryGuard(config.memoryBudget)
+		}
+		switch {
+		case result != nil && prefetch != nil:
+			prefetch.discard(i)
+			discarded = true
+		case result != nil && decoded != nil:
+			delete(decoded, i)
+		case result == nil && decoded != nil:
+			result = processPayload(decoded[i].payload, decoded[i].result)
+			delete(decoded, i)
+		case result == nil && prefetch != nil:
+			var decoded *Payload
+			if decoded, result = prefetch.get(i); decoded != nil {
+				result = processPayload(decoded, result)
+			}
+		case result == nil:
+			result = process(payload)
+		}
+		if guard != nil {
+			if used := guard.stop(); used > config.memoryBudget {
+				result.Valid = false
+				result.fail(ExitMemoryExceeded, "memory limit exceeded: payload used %d bytes, limit is %d", used, config.memoryBudget)
+			}
+		}
+		if names != nil {
+			result.Member = names[i]
+		}
+		if !resumedResult {
+			if outdir != "" {
+				if err := writeResultFile(outdir, i, result); err != nil {
+					logger.Error("Failed to write result", "payload", label(i), "err", err)
+					return ExitBatchFailed
+				}
+			}
+			if config.append != "" {
+				if err := appendResult(config.append, result); err != nil {
+					logger.Error("Failed to append result", "payload", label(i), "err", err)
+					return ExitBatchFailed
+				}
+			}
+		}
+		if config.metrics != nil {
+			if err := config.metrics.record(result); err != nil {
+				logger.Error("Failed to write metrics", "payload", label(i), "err", err)
+			}
+		}
+		if config.print != nil && !(config.errorsOnly && (result.Valid || result.Deferred)) {
+			if err := config.print(result); err != nil {
+				logger.Error("Failed to write result", "payload", label(i), "err", err)
+				return ExitBatchFailed
+			}
+		}
+		switch {
+		case result.Deferred:
+			deferred++
+		case result.Valid:
+			valid++
+		default:
+			failed++
+		}
+		logResult(result, "payload", label(i))
+		if config.continuity && previous != nil {
+			parent, child := previous, result
+			if config.reverse {
+				parent, child = result, previous
+			}
+			if err := checkContinuity(parent, child); err != nil {
+				broken++
+				logger.Error("Chain continuity broken", "payload", label(i), "err", err)
+			}
+		}
+		previous = result
+
+		if config.checkpoint != "" {
+			progress := &checkpoint{Index: i, Valid: valid, Failed: failed, Deferred: deferred, Unlinked: broken, Last: result}
+			if err := writeCheckpoint(config.checkpoint, progress); err != nil {
+				logger.Error("Failed to write checkpoint", "payload", label(i), "err", err)
+				return ExitBatchFailed
+			}
+		}
+
+		// With the garbage collector disabled, collect the garbage left behind
+		// by the payload explicitly so memory use stays bounded by the largest
+		// payload rather than growing with the batch. Aggressive freeing also
+		// drops the input of the payload, unless a discarded prefetch may still
+		// be reading it, and returns the memory to the operating system.
+		switch {
+		case config.aggressiveFree:
+			if !discarded {
+				payloads[i] = nil
+			}
+			debug.FreeOSMemory()
+		case config.collect:
+			runtime.GC()
+		}
+	}
+	logger.Info("Batch finished", "valid", valid, "failed", failed, "deferred", deferred, "resumed", resumed, "unsampled", skipped, "processed", already, "unlinked", broken)
+	if failed > 0 || broken > 0 {
+		return ExitBatchFailed
+	}
+	return ExitSuccess
+}
+
+// decodeAll decodes and structurally validates the payloads at the given batch
+// positions, except those the skip function reports as needing no validation,
+// reporting every failure on stderr. It returns the decoded payloads along with
+// the number of failures.
+func decodeAll(payloads [][]byte, order []int, skip func(int) bool, label func(int) string) (map[int]decodedPayload, int) {
+	var (
+		decoded  = make(map[int]decodedPayload, len(order))
+		failures int
+	)
+	for _, i := range order {
+		if skip(i) {
+			continue
+		}
+		payload, result := decodePayload(payloads[i])
+		if payload != nil {
+			if err := validatePayload(payload); err != nil {
+				result.fail(ExitValidationFailed, "payload validation failed: %w", err)
+				payload = nil
+			}
+		}
+		if payload == nil {
+			failures++
+			logResult(result, "payload", label(i))
+			continue
+		}
+		decoded[i] = decodedPayload{payload, result}
+	}
+	return decoded, failures
+}
+
+// resultFilePath returns the path of the result file of the payload at the
+// given position of the batch.
+func resultFilePath(dir string, index int) string {
+	return filepath.Join(dir, fmt.Sprintf("payload-%06d.json", index))
+}
+
+// readResultFile loads the result of a payload written by a previous run.
+func readResultFile(dir string, index int) (*Result, error) {
+	blob, err := os.ReadFile(resultFilePath(dir, index))
+	if err != nil {
+		return nil, err
+	}
+	result := new(Result)
+	if err := json.Unmarshal(blob, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// writeResultFile durably stores the result of a payload. The file is written
+// atomically, so a crash never leaves a partial result behind to be mistaken
+// for a completed one.
+func writeResultFile(dir string, index int, result *Result) error {
+	blob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(resultFilePath(dir, index), append(blob, '\n'))
+}
+
+// writeFileAtomic durably writes the data to the file at the given path. The
+// data is written under a temporary name in the same directory and renamed
+// into place, so readers never observe a partially written file. The directory
+// is synced after the rename, so that the new file survives a crash as well.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
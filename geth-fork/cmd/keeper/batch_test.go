@@ -0,0 +1,297 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// makeBatch frames the given payloads into a batch input.
+func makeBatch(payloads ...[]byte) []byte {
+	var batch []byte
+	for _, payload := range payloads {
+		batch = binary.BigEndian.AppendUint32(batch, uint32(len(payload)))
+		batch = append(batch, payload...)
+	}
+	return batch
+}
+
+// TestSplitBatch tests framing of batch inputs.
+func TestSplitBatch(t *testing.T) {
+	payloads, err := splitBatch(makeBatch([]byte{0xc0}, []byte{0xc1, 0x80}, nil))
+	if err != nil {
+		t.Fatalf("splitBatch failed: %v", err)
+	}
+	if len(payloads) != 3 || !bytes.Equal(payloads[1], []byte{0xc1, 0x80}) || len(payloads[2]) != 0 {
+		t.Fatalf("unexpected payloads: %x", payloads)
+	}
+	if _, err := splitBatch([]byte{0x00, 0x00}); err == nil {
+		t.Error("expected error for truncated length prefix")
+	}
+	if _, err := splitBatch([]byte{0x00, 0x00, 0x00, 0x05, 0xc0}); err == nil {
+		t.Error("expected error for truncated payload")
+	}
+}
+
+// TestBatchPartialOutput tests that every result is written to the output
+// directory and that a rerun resumes from the stored results.
+func TestBatchPartialOutput(t *testing.T) {
+	dir := t.TempDir()
+	batch := makeBatch([]byte{0x05}, []byte{0x85, 0x68, 0x65, 0x6c, 0x6c, 0x6f})
+
+	if code := runBatch(batch, &batchConfig{outdir: dir}); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	for i := 0; i < 2; i++ {
+		result, err := readResultFile(dir, i)
+		if err != nil {
+			t.Fatalf("result %d not written: %v", i, err)
+		}
+		if result.Valid || result.ExitCode != ExitInvalidInput {
+			t.Errorf("result %d = %+v, want invalid input", i, result)
+		}
+	}
+	// Stored results take precedence over revalidation on restart.
+	for i := 0; i < 2; i++ {
+		if err := writeResultFile(dir, i, &Result{Valid: true}); err != nil {
+			t.Fatalf("failed to overwrite result %d: %v", i, err)
+		}
+	}
+	if code := runBatch(batch, &batchConfig{outdir: dir}); code != ExitSuccess {
+		t.Fatalf("resumed exit code = %d, want %d", code, ExitSuccess)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("output directory holds %d entries, want 2", len(entries))
+	}
+}
+
+// TestBatchChainContinuity tests that parent hash links between consecutive
+// payloads are checked in both forward and reverse order.
+func TestBatchChainContinuity(t *testing.T) {
+	chain := []*Result{
+		{Number: 1, Hash: common.Hash{0x01}, ParentHash: common.Hash{0x00}, Valid: true},
+		{Number: 2, Hash: common.Hash{0x02}, ParentHash: common.Hash{0x01}, Valid: true},
+		{Number: 3, Hash: common.Hash{0x03}, ParentHash: common.Hash{0x02}, Valid: true},
+	}
+	tests := []struct {
+		results []*Result
+		reverse bool
+		want    int
+	}{
+		{chain, false, ExitSuccess},
+		{chain, true, ExitSuccess},
+		{[]*Result{chain[0], chain[2]}, false, ExitBatchFailed},
+		{[]*Result{chain[0], chain[2]}, true, ExitBatchFailed},
+		{[]*Result{chain[1], chain[0]}, true, ExitBatchFailed},
+	}
+	for i, tt := range tests {
+		// Stored results stand in for validation of the payloads themselves.
+		dir := t.TempDir()
+		payloads := make([][]byte, len(tt.results))
+		for j, result := range tt.results {
+			if err := writeResultFile(dir, j, result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		config := &batchConfig{outdir: dir, reverse: tt.reverse, continuity: true}
+		if code := runBatch(makeBatch(payloads...), config); code != tt.want {
+			t.Errorf("test %d: exit code = %d, want %d", i, code, tt.want)
+		}
+	}
+}
+
+// TestBatchTwoPhase tests that no payload is executed in two-phase mode if any
+// of them fails to decode.
+func TestBatchTwoPhase(t *testing.T) {
+	good := makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)
+
+	var results []*Result
+	config := &batchConfig{twoPhase: true, print: func(result *Result) error {
+		results = append(results, result)
+		return nil
+	}}
+	if code := runBatch(makeBatch(good, []byte{0x05}, []byte{0xc1, 0xc0}), config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(results) != 0 {
+		t.Fatalf("%d payloads executed despite decode failures", len(results))
+	}
+	if code := runBatch(makeBatch(good, good), config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(results) != 2 || results[0].ExitCode != ExitStateRootMismatch || results[1].ExitCode != ExitStateRootMismatch {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
+
+// TestDecodeAllSkip tests that two-phase decoding leaves out the payloads that
+// are resumed or deferred, so that they are not held in memory for the batch.
+func TestDecodeAllSkip(t *testing.T) {
+	good := makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)
+	payloads := [][]byte{good, {0x05}, good}
+
+	skip := func(i int) bool { return i == 1 || i == 2 }
+	decoded, failures := decodeAll(payloads, []int{0, 1, 2}, skip, strconv.Itoa)
+	if failures != 0 {
+		t.Errorf("%d failures, want none", failures)
+	}
+	if _, ok := decoded[0]; !ok || len(decoded) != 1 {
+		t.Errorf("%d payloads decoded, want only payload 0", len(decoded))
+	}
+}
+
+// TestBatchSinceBlock tests that payloads at or below the since-block watermark
+// are skipped, while undecodable ones are still reported.
+func TestBatchSinceBlock(t *testing.T) {
+	var numbers []uint64
+	config := &batchConfig{sinceBlock: 2, print: func(result *Result) error {
+		numbers = append(numbers, result.Number)
+		return nil
+	}}
+	batch := makeBatch(makeGasPayload(t, 1, 0), makeGasPayload(t, 2, 0), []byte{0x05}, makeGasPayload(t, 3, 0))
+	if code := runBatch(batch, config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(numbers) != 2 || numbers[0] != 0 || numbers[1] != 3 {
+		t.Errorf("validated blocks %v, want [0 3]", numbers)
+	}
+}
+
+// TestBatchErrorsOnly tests that only failed results are printed in errors-only
+// mode, while results of every payload are still recorded.
+func TestBatchErrorsOnly(t *testing.T) {
+	var printed []*Result
+	config := &batchConfig{outdir: t.TempDir(), errorsOnly: true, print: func(result *Result) error {
+		printed = append(printed, result)
+		return nil
+	}}
+	// Stored valid results stand in for passing payloads.
+	for i := 0; i < 2; i++ {
+		if err := writeResultFile(config.outdir, i, &Result{Valid: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if code := runBatch(makeBatch(nil, nil, []byte{0x05}), config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(printed) != 1 || printed[0].ExitCode != ExitInvalidInput {
+		t.Errorf("printed results %+v, want the invalid payload only", printed)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := readResultFile(config.outdir, i); err != nil {
+			t.Errorf("result %d not recorded: %v", i, err)
+		}
+	}
+}
+
+// TestBatchCheckpoint tests that a batch restarted with a checkpoint continues
+// after the recorded payload, carrying over the counts of the earlier run, and
+// that checkpoints of another batch are refused.
+func TestBatchCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	batch := makeBatch(makeGasPayload(t, 1, 0), makeGasPayload(t, 2, 0), makeGasPayload(t, 3, 0))
+
+	var numbers []uint64
+	config := &batchConfig{checkpoint: path, print: func(result *Result) error {
+		numbers = append(numbers, result.Number)
+		return nil
+	}}
+	if code := runBatch(batch, config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	cp, err := readCheckpoint(path)
+	if err != nil || cp == nil {
+		t.Fatalf("checkpoint not written: %v", err)
+	}
+	if cp.Index != 2 || cp.Failed != 3 || cp.Last.Number != 3 {
+		t.Fatalf("unexpected checkpoint %+v", cp)
+	}
+	// Rewind the checkpoint to the first payload, as if the run had crashed
+	// while validating the second one.
+	_, header, err := peekHeader(makeGasPayload(t, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := &checkpoint{Index: 0, Failed: 1, Last: &Result{Number: 1, Hash: header.Hash()}}
+	if err := writeCheckpoint(path, first); err != nil {
+		t.Fatal(err)
+	}
+	numbers = nil
+	if code := runBatch(batch, config); code != ExitBatchFailed {
+		t.Fatalf("resumed exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(numbers) != 2 || numbers[0] != 2 || numbers[1] != 3 {
+		t.Errorf("resumed run validated blocks %v, want [2 3]", numbers)
+	}
+	if cp, err := readCheckpoint(path); err != nil || cp.Index != 2 || cp.Failed != 3 {
+		t.Errorf("resumed checkpoint %+v (%v), want index 2 with 3 failures", cp, err)
+	}
+	// A checkpoint naming a block the batch does not hold at that position is
+	// rejected rather than silently skipping payloads.
+	if err := writeCheckpoint(path, &checkpoint{Index: 1, Last: &Result{Hash: common.Hash{0x01}}}); err != nil {
+		t.Fatal(err)
+	}
+	if code := runBatch(batch, config); code != ExitInvalidInput {
+		t.Errorf("mismatching checkpoint: exit code = %d, want %d", code, ExitInvalidInput)
+	}
+}
+
+// TestBatchInterrupt tests that an interrupted batch stops after the payload in
+// progress, with its checkpoint recording it, and resumes after it.
+func TestBatchInterrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	batch := makeBatch(makeGasPayload(t, 1, 0), makeGasPayload(t, 2, 0), makeGasPayload(t, 3, 0))
+
+	var (
+		numbers     []uint64
+		interrupted = make(chan struct{})
+	)
+	config := &batchConfig{checkpoint: path, interrupted: interrupted, print: func(result *Result) error {
+		if numbers = append(numbers, result.Number); result.Number == 1 {
+			close(interrupted)
+		}
+		return nil
+	}}
+	if code := runBatch(batch, config); code != ExitInterrupted {
+		t.Fatalf("exit code = %d, want %d", code, ExitInterrupted)
+	}
+	if len(numbers) != 1 || numbers[0] != 1 {
+		t.Fatalf("interrupted run validated blocks %v, want [1]", numbers)
+	}
+	if cp, err := readCheckpoint(path); err != nil || cp == nil || cp.Index != 0 {
+		t.Fatalf("checkpoint %+v (%v), want index 0", cp, err)
+	}
+	numbers, config.interrupted = nil, nil
+	if code := runBatch(batch, config); code != ExitBatchFailed {
+		t.Fatalf("resumed exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(numbers) != 2 || numbers[0] != 2 || numbers[1] != 3 {
+		t.Errorf("resumed run validated blocks %v, want [2 3]", numbers)
+	}
+}
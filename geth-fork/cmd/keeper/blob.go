@@ -0,0 +1,60 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// verifyBlobGas checks the EIP-4844 blob gas accounting of the block before
+// execution. Stateless execution validates the resulting state, not the block
+// body against its header, so without this check the blob gas fields would be
+// taken on trust. Blocks before Cancun must not carry blob transactions. From
+// Cancun on, the blob gas used declared in the header must match the blobs
+// referenced by the transactions and stay within the limit of the fork, and
+// the excess blob gas must follow from the parent header.
+func verifyBlobGas(config *params.ChainConfig, block *types.Block, parent *types.Header) error {
+	var blobs int
+	for _, tx := range block.Transactions() {
+		blobs += len(tx.BlobHashes())
+	}
+	header := block.Header()
+	if !config.IsCancun(header.Number, header.Time) {
+		if blobs > 0 {
+			return fmt.Errorf("block has %d blobs, but Cancun is not active for block %d on chain %v", blobs, header.Number, config.ChainID)
+		}
+		return nil
+	}
+	if header.BlobGasUsed == nil || header.ExcessBlobGas == nil {
+		return errors.New("header lacks blob gas fields")
+	}
+	if used := uint64(blobs) * params.BlobTxBlobGasPerBlob; *header.BlobGasUsed != used {
+		return fmt.Errorf("blob gas used mismatch (header: %d, transactions: %d for %d blobs)", *header.BlobGasUsed, used, blobs)
+	}
+	if parent == nil {
+		return errors.New("witness has no parent header")
+	}
+	if parent.Number.Uint64()+1 != header.Number.Uint64() {
+		return fmt.Errorf("witness parent header is block %d, want %d", parent.Number, header.Number.Uint64()-1)
+	}
+	return eip4844.VerifyEIP4844Header(config, parent, header)
+}
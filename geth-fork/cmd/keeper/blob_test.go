@@ -0,0 +1,67 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestVerifyBlobGas tests that the blob gas fields of a header are checked
+// against the blob transactions of the block and the parent header.
+func TestVerifyBlobGas(t *testing.T) {
+	config := params.MainnetChainConfig
+	cancun := *config.CancunTime
+
+	zero := uint64(0)
+	parent := &types.Header{Number: big.NewInt(19_500_000), Time: cancun, BlobGasUsed: &zero, ExcessBlobGas: &zero}
+	blobTx := types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{{0x01}, {0x02}}})
+
+	block := func(number int64, time uint64, used, excess *uint64, txs ...*types.Transaction) *types.Block {
+		header := &types.Header{Number: big.NewInt(number), Time: time, BlobGasUsed: used, ExcessBlobGas: excess}
+		return types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: txs})
+	}
+	ptr := func(v uint64) *uint64 { return &v }
+
+	used := ptr(2 * params.BlobTxBlobGasPerBlob)
+	excess := ptr(eip4844.CalcExcessBlobGas(config, parent, cancun+12))
+	tests := []struct {
+		block  *types.Block
+		parent *types.Header
+		valid  bool
+	}{
+		{block(19_500_001, cancun+12, used, excess, blobTx), parent, true},
+		{block(19_500_001, cancun+12, ptr(0), excess), parent, true},
+		{block(19_500_001, cancun+12, ptr(0), excess, blobTx), parent, false},
+		{block(19_500_001, cancun+12, used, ptr(*excess+1), blobTx), parent, false},
+		{block(19_500_001, cancun+12, nil, nil), parent, false},
+		{block(19_500_002, cancun+12, used, excess, blobTx), parent, false},
+		{block(19_500_001, cancun+12, used, excess, blobTx), nil, false},
+		{block(19_000_000, cancun-1, nil, nil, blobTx), nil, false},
+		{block(19_000_000, cancun-1, nil, nil), nil, true},
+	}
+	for i, tt := range tests {
+		if err := verifyBlobGas(config, tt.block, tt.parent); (err == nil) != tt.valid {
+			t.Errorf("test %d: error %v, want valid %v", i, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/version"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// BuildInfo identifies the keeper build that produced a result, so that the
+// result can be reproduced with the same build later.
+type BuildInfo struct {
+	Version string `json:"version"`          // Keeper version, including the commit if known
+	Geth    string `json:"geth"`             // Version of go-ethereum the keeper was built against
+	Commit  string `json:"commit,omitempty"` // Commit the keeper was built from, suffixed with -dirty for modified trees
+	Go      string `json:"go"`               // Go toolchain the keeper was built with
+}
+
+// currentBuild returns the build information of the running binary.
+var currentBuild = sync.OnceValue(readBuildInfo)
+
+// readBuildInfo collects the build information of the running binary. The
+// commit is taken from the linker flags set by the geth build script if
+// present, and otherwise from the VCS information the go tool embeds into
+// builds from a git checkout.
+func readBuildInfo() *BuildInfo {
+	build := &BuildInfo{Geth: version.WithMeta, Go: runtime.Version()}
+	git, _ := version.VCS()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if git.Commit == "" {
+			for _, setting := range info.Settings {
+				switch setting.Key {
+				case "vcs.revision":
+					git.Commit = setting.Value
+				case "vcs.time":
+					if t, err := time.Parse(time.RFC3339, setting.Value); err == nil {
+						git.Date = t.Format("20060102")
+					}
+				case "vcs.modified":
+					git.Dirty = setting.Value == "true"
+				}
+			}
+		}
+		// Within this repository, go-ethereum is replaced by the enclosing
+		// tree, whose version is the one compiled in.
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/ethereum/go-ethereum" && dep.Replace == nil {
+				build.Geth = dep.Version
+			}
+		}
+	}
+	build.Version = version.WithCommit(git.Commit, git.Date)
+	if build.Commit = git.Commit; git.Dirty {
+		build.Commit += "-dirty"
+	}
+	return build
+}
+
+// runVersion runs the version subcommand: it prints the build information of
+// the keeper and the networks it has a built-in configuration for.
+func runVersion(out io.Writer) int {
+	build := currentBuild()
+	fmt.Fprintf(out, "keeper:      %s\n", build.Version)
+	fmt.Fprintf(out, "go-ethereum: %s\n", build.Geth)
+	if build.Commit != "" {
+		fmt.Fprintf(out, "commit:      %s\n", build.Commit)
+	}
+	fmt.Fprintf(out, "go:          %s %s/%s\n", build.Go, runtime.GOOS, runtime.GOARCH)
+	var chains []string
+	for _, id := range slices.Sorted(maps.Keys(builtinChains)) {
+		chains = append(chains, fmt.Sprintf("%d (%s)", id, params.NetworkNames[strconv.FormatUint(id, 10)]))
+	}
+	fmt.Fprintf(out, "chains:      %s\n", strings.Join(chains, ", "))
+	fmt.Fprintf(out, "keccak:      %s (available: %s)\n", crypto.KeccakBackend(), strings.Join(crypto.KeccakBackends(), ", "))
+	return ExitSuccess
+}
+
+// keeperVersion returns the version of the keeper binary, including the commit
+// it was built from if the build carries VCS information.
+func keeperVersion() string {
+	return currentBuild().Version
+}
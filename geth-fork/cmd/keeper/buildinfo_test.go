@@ -0,0 +1,65 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/internal/version"
+)
+
+// TestBuildInfo tests that results record the build that produced them.
+func TestBuildInfo(t *testing.T) {
+	build := currentBuild()
+	if !strings.HasPrefix(build.Version, version.Semantic) || build.Geth == "" || build.Go != runtime.Version() {
+		t.Fatalf("unexpected build info %+v", build)
+	}
+	if build.Commit != "" && !strings.Contains(build.Version, build.Commit[:8]) {
+		t.Errorf("version %q does not name commit %q", build.Version, build.Commit)
+	}
+	for _, input := range [][]byte{{0x05}, makeGasPayload(t, 1, 0)} {
+		blob, err := json.Marshal(process(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result struct{ Build *BuildInfo }
+		if err := json.Unmarshal(blob, &result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Build == nil || *result.Build != *build {
+			t.Errorf("result %s does not carry the build info", blob)
+		}
+	}
+}
+
+// TestVersion tests that the version subcommand reports the build, the built-in
+// networks and the Keccak backends.
+func TestVersion(t *testing.T) {
+	var out bytes.Buffer
+	if code := runVersion(&out); code != ExitSuccess {
+		t.Fatalf("exit code %d", code)
+	}
+	for _, want := range []string{currentBuild().Version, runtime.Version(), "1 (mainnet)", "11155111 (sepolia)", "560048 (hoodi)", "keccak:      standard"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output %q does not mention %q", out.String(), want)
+		}
+	}
+}
@@ -17,22 +17,62 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// builtinChains holds the configurations of the networks built into the keeper,
+// by chain ID. Payloads with a zero chain ID are validated as mainnet.
+var builtinChains = map[uint64]*params.ChainConfig{
+	1:        params.MainnetChainConfig,
+	17000:    params.HoleskyChainConfig,
+	560048:   params.HoodiChainConfig,
+	11155111: params.SepoliaChainConfig,
+}
+
+// customChainConfig is the chain configuration loaded with --chain-config. If
+// set, it replaces the built-in configurations.
+var customChainConfig *params.ChainConfig
+
+// loadChainConfig loads the chain configuration from a genesis file in the
+// go-ethereum genesis JSON format.
+func loadChainConfig(path string) (*params.ChainConfig, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(blob, genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %v", err)
+	}
+	if genesis.Config == nil {
+		return nil, errors.New("genesis file has no chain config")
+	}
+	if genesis.Config.ChainID == nil || genesis.Config.ChainID.Sign() == 0 {
+		return nil, errors.New("genesis file has no chain ID")
+	}
+	return genesis.Config, nil
+}
+
 // getChainConfig returns the appropriate chain configuration based on the chainID.
 // Returns an error for unsupported chain IDs.
 func getChainConfig(chainID uint64) (*params.ChainConfig, error) {
-	switch chainID {
-	case 0, params.MainnetChainConfig.ChainID.Uint64():
+	if customChainConfig != nil {
+		if id := customChainConfig.ChainID.Uint64(); id != chainID {
+			return nil, fmt.Errorf("payload chain ID %d does not match chain ID %d of --chain-config: %w", chainID, id, &UnknownChainIDError{ChainID: chainID})
+		}
+		return customChainConfig, nil
+	}
+	if chainID == 0 {
 		return params.MainnetChainConfig, nil
-	case params.SepoliaChainConfig.ChainID.Uint64():
-		return params.SepoliaChainConfig, nil
-	case params.HoodiChainConfig.ChainID.Uint64():
-		return params.HoodiChainConfig, nil
-	default:
-		return nil, fmt.Errorf("unsupported chain ID: %d", chainID)
 	}
+	if config, ok := builtinChains[chainID]; ok {
+		return config, nil
+	}
+	return nil, &UnknownChainIDError{ChainID: chainID}
 }
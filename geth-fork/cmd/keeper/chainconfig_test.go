@@ -0,0 +1,80 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestCustomChainConfig tests that a chain config loaded from a genesis file
+// replaces the built-in networks.
+func TestCustomChainConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	genesis := `{"config":{"chainId":4242,"homesteadBlock":0,"eip150Block":0,"eip155Block":0,"eip158Block":0,"byzantiumBlock":0,"clique":{"period":5,"epoch":30000}},"difficulty":"1","gasLimit":"8000000","alloc":{}}`
+	if err := os.WriteFile(path, []byte(genesis), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadChainConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load chain config: %v", err)
+	}
+	if config.ChainID.Uint64() != 4242 || config.Clique == nil || config.Clique.Period != 5 {
+		t.Fatalf("unexpected chain config %v", config)
+	}
+	defer func() { customChainConfig = nil }()
+	customChainConfig = config
+
+	if got, err := getChainConfig(4242); err != nil || got != config {
+		t.Errorf("custom chain not resolved: %v", err)
+	}
+	var chainErr *UnknownChainIDError
+	if _, err := getChainConfig(1); !errors.As(err, &chainErr) || chainErr.ChainID != 1 {
+		t.Errorf("built-in chain not replaced: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"difficulty":"1"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadChainConfig(path); err == nil {
+		t.Error("expected error for genesis without chain config")
+	}
+}
+
+// TestBuiltinChains tests that the built-in networks resolve by their chain ID
+// and that unknown chain IDs are rejected.
+func TestBuiltinChains(t *testing.T) {
+	for id, config := range builtinChains {
+		if config.ChainID.Uint64() != id {
+			t.Errorf("chain %d holds the config of chain %v", id, config.ChainID)
+		}
+	}
+	if config, err := getChainConfig(11155111); err != nil || config != params.SepoliaChainConfig {
+		t.Errorf("sepolia not resolved: %v", err)
+	}
+	if config, err := getChainConfig(0); err != nil || config != params.MainnetChainConfig {
+		t.Errorf("zero chain ID not resolved as mainnet: %v", err)
+	}
+	var chainErr *UnknownChainIDError
+	if _, err := getChainConfig(99999); !errors.As(err, &chainErr) || chainErr.ChainID != 99999 {
+		t.Errorf("unknown chain not rejected: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// checkpoint records the progress of a batch run, allowing it to resume after
+// the last processed payload instead of starting over. The counters cover all
+// payloads processed so far, so the summary and exit code of a resumed run
+// reflect the whole batch.
+type checkpoint struct {
+	Index    int     `json:"index"`    // Batch position of the last processed payload
+	Valid    int     `json:"valid"`    // Number of payloads found valid
+	Failed   int     `json:"failed"`   // Number of payloads that failed
+	Deferred int     `json:"deferred"` // Number of payloads deferred
+	Unlinked int     `json:"unlinked"` // Number of chain continuity breaks
+	Last     *Result `json:"last"`     // Result of the last processed payload
+}
+
+// readCheckpoint loads the checkpoint at the given path. A missing file is not
+// an error, it means the run starts from the beginning.
+func readCheckpoint(path string) (*checkpoint, error) {
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := new(checkpoint)
+	if err := json.Unmarshal(blob, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %v", err)
+	}
+	if cp.Last == nil {
+		return nil, errors.New("invalid checkpoint: missing last result")
+	}
+	return cp, nil
+}
+
+// writeCheckpoint durably stores the checkpoint, replacing the previous one
+// atomically so that a crash leaves either of them intact.
+func writeCheckpoint(path string, cp *checkpoint) error {
+	blob, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(blob, '\n'))
+}
+
+// resumeOrder returns the part of the processing order following the payload
+// recorded in the checkpoint. The payload at the recorded position must still
+// hold the recorded block, to catch checkpoints left behind by another batch.
+func resumeOrder(order []int, payloads [][]byte, cp *checkpoint) ([]int, error) {
+	for n, i := range order {
+		if i != cp.Index {
+			continue
+		}
+		if cp.Last.Hash != (common.Hash{}) {
+			if _, header, err := peekHeader(payloads[i]); err != nil || header.Hash() != cp.Last.Hash {
+				return nil, fmt.Errorf("payload %d does not hold the checkpointed block %x", i, cp.Last.Hash)
+			}
+		}
+		return order[n+1:], nil
+	}
+	return nil, fmt.Errorf("checkpointed payload %d is not part of the batch", cp.Index)
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestCliqueSigner tests that the sealer of a Clique block is recovered from
+// the seal in its extra data.
+func TestCliqueSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(2), Extra: make([]byte, 32+crypto.SignatureLength)}
+	seal, err := crypto.Sign(clique.SealHash(header).Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(header.Extra[32:], seal)
+
+	signer, err := cliqueSigner(header)
+	if err != nil {
+		t.Fatalf("failed to recover signer: %v", err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); signer != want {
+		t.Errorf("signer %x, want %x", signer, want)
+	}
+	// A header sealed by someone else, or modified after sealing, yields
+	// another signer rather than an error.
+	header.GasLimit = 1
+	if signer, err := cliqueSigner(header); err == nil && signer == crypto.PubkeyToAddress(key.PublicKey) {
+		t.Error("signer recovered from a modified header")
+	}
+	if _, err := cliqueSigner(&types.Header{Number: big.NewInt(1), Extra: make([]byte, 32)}); err == nil {
+		t.Error("signer recovered from extra data without a seal")
+	}
+}
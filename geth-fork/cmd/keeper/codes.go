@@ -0,0 +1,105 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Positions of the code and state lists in the encoded witness.
+const (
+	witnessCodesIndex = 1
+	witnessStateIndex = 2
+)
+
+// verifyWitnessCodes checks the bytecodes of the witness in an encoded payload
+// one at a time, before the payload is decoded. The witness does not declare
+// the hashes of its codes; they are referenced by the accounts in its state
+// trie nodes. Those references are gathered from the raw nodes first, then each
+// code is hashed and must be referenced by an account, stopping at the first
+// that is not. A code that was corrupted in transit is caught this way without
+// decoding the remainder of the witness.
+func verifyWitnessCodes(input []byte) error {
+	elems, err := rlp.SplitListValues(input)
+	if err != nil || len(elems) != 3 {
+		return nil // Not a payload, left to the decoder to report
+	}
+	witness, err := rlp.SplitListValues(elems[2])
+	if err != nil || len(witness) <= witnessStateIndex {
+		return nil
+	}
+	nodes, err := rlp.SplitListValues(witness[witnessStateIndex])
+	if err != nil {
+		return nil
+	}
+	referenced := make(map[common.Hash]struct{})
+	for _, node := range nodes {
+		if content, _, err := rlp.SplitString(node); err == nil {
+			if hash, ok := accountCodeHash(content); ok {
+				referenced[hash] = struct{}{}
+			}
+		}
+	}
+	codes, _, err := rlp.SplitList(witness[witnessCodesIndex])
+	if err != nil {
+		return nil
+	}
+	for i := 0; len(codes) > 0; i++ {
+		code, rest, err := rlp.SplitString(codes)
+		if err != nil {
+			return nil
+		}
+		hash := crypto.Keccak256Hash(code)
+		if _, ok := referenced[hash]; !ok {
+			return fmt.Errorf("witness code %d with hash %x is not referenced by any account", i, hash)
+		}
+		codes = rest
+	}
+	return nil
+}
+
+// accountCodeHash returns the code hash of the account stored in the given
+// trie node, if it is a leaf of the account trie. Account leaves are short
+// nodes whose key carries the terminator flag and whose value is the encoded
+// account, a list of nonce, balance, storage root and code hash.
+func accountCodeHash(node []byte) (common.Hash, bool) {
+	elems, err := rlp.SplitListValues(node)
+	if err != nil || len(elems) != 2 {
+		return common.Hash{}, false
+	}
+	key, _, err := rlp.SplitString(elems[0])
+	if err != nil || len(key) == 0 || key[0]>>4 < 2 {
+		return common.Hash{}, false
+	}
+	value, _, err := rlp.SplitString(elems[1])
+	if err != nil {
+		return common.Hash{}, false
+	}
+	account, err := rlp.SplitListValues(value)
+	if err != nil || len(account) != 4 {
+		return common.Hash{}, false
+	}
+	codeHash, _, err := rlp.SplitString(account[3])
+	if err != nil || len(codeHash) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(codeHash), true
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
+)
+
+// TestVerifyWitnessCodes tests that witness codes are checked against the code
+// hashes of the accounts in the witness state.
+func TestVerifyWitnessCodes(t *testing.T) {
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+
+	// Build the pre-state trie nodes for an account holding the code.
+	var nodes []string
+	tr := trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+		nodes = append(nodes, string(blob))
+	})
+	account, _ := rlp.EncodeToBytes(&types.StateAccount{Balance: new(uint256.Int), Root: types.EmptyRootHash, CodeHash: crypto.Keccak256(code)})
+	tr.Update(crypto.Keccak256(common.Address{0xaa}.Bytes()), account)
+	root := tr.Hash()
+
+	encode := func(codes ...[]byte) []byte {
+		parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: root}
+		header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash()}
+		witness, _ := stateless.NewWitness(header, nil)
+		witness.Headers = []*types.Header{parent}
+		for _, node := range nodes {
+			witness.State[node] = struct{}{}
+		}
+		for _, code := range codes {
+			witness.Codes[string(code)] = struct{}{}
+		}
+		input, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: types.NewBlockWithHeader(header), Witness: witness})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return input
+	}
+	if err := verifyWitnessCodes(encode(code)); err != nil {
+		t.Errorf("referenced code rejected: %v", err)
+	}
+	corrupt := append([]byte{}, code...)
+	corrupt[0] = 0x61
+	err := verifyWitnessCodes(encode(corrupt))
+	if err == nil || !strings.Contains(err.Error(), crypto.Keccak256Hash(corrupt).Hex()[2:]) {
+		t.Errorf("corrupted code not reported by hash: %v", err)
+	}
+	defer func(verify bool) { *verifyCodes = verify }(*verifyCodes)
+	*verifyCodes = true
+	if result := process(encode(corrupt)); result.ExitCode != ExitWitnessInvalid || result.Stage != stageDecode {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// nondeterministicFields lists the fields of a JSON result that may differ
+// between runs or versions over the same payload, such as the build
+// information, and are never compared by compat-check.
+var nondeterministicFields = []string{"build"}
+
+// runCompatCheck runs the compat-check subcommand: it validates a payload and
+// compares the JSON result field by field with an expected result, typically
+// recorded by an earlier keeper version. Differences are listed on stderr.
+func runCompatCheck(args []string) int {
+	fs := flag.NewFlagSet("compat-check", flag.ContinueOnError)
+	var (
+		payloadPath = fs.String("payload", "", "file holding the payload to validate (encoded as per --format)")
+		expectPath  = fs.String("expect", "", "file holding the expected JSON result")
+		ignore      = fs.String("ignore", "", "comma-separated list of additional result fields to skip")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *payloadPath == "" || *expectPath == "" || fs.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Error: compat-check requires --payload and --expect")
+		fs.Usage()
+		return 2
+	}
+	raw, err := readInputFile(*payloadPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read payload: %v\n", err)
+		return ExitInvalidInput
+	}
+	input, _, err := decodeInput(raw, *inputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "input decoding failed: %v\n", err)
+		return ExitInvalidInput
+	}
+	expected, err := os.ReadFile(*expectPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read expected result: %v\n", err)
+		return ExitInvalidInput
+	}
+	skip := slices.Clone(nondeterministicFields)
+	if *ignore != "" {
+		skip = append(skip, strings.Split(*ignore, ",")...)
+	}
+	diffs, err := compareResults(process(input), expected, skip)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to compare results: %v\n", err)
+		return ExitInvalidInput
+	}
+	for _, diff := range diffs {
+		fmt.Fprintln(os.Stderr, diff)
+	}
+	if len(diffs) > 0 {
+		fmt.Fprintf(os.Stderr, "compat-check: %d fields differ\n", len(diffs))
+		return 1
+	}
+	fmt.Fprintln(os.Stderr, "compat-check: result matches")
+	return ExitSuccess
+}
+
+// compareResults compares the JSON encoding of the result with the expected
+// JSON result, skipping the given fields, and returns one line per field that
+// differs, sorted by field name.
+func compareResults(result *Result, expected []byte, skip []string) ([]string, error) {
+	var buf bytes.Buffer
+	if err := writeResult(&buf, result); err != nil {
+		return nil, err
+	}
+	got, err := decodeJSONFields(&buf)
+	if err != nil {
+		return nil, err
+	}
+	want, err := decodeJSONFields(bytes.NewReader(expected))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expected result: %v", err)
+	}
+	for _, field := range skip {
+		delete(got, field)
+		delete(want, field)
+	}
+	var fields []string
+	for field := range got {
+		fields = append(fields, field)
+	}
+	for field := range want {
+		if _, ok := got[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var diffs []string
+	for _, field := range fields {
+		g, inGot := got[field]
+		w, inWant := want[field]
+		switch {
+		case !inWant:
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected field with value %s", field, g))
+		case !inGot:
+			diffs = append(diffs, fmt.Sprintf("%s: missing, want %s", field, w))
+		case g != w:
+			diffs = append(diffs, fmt.Sprintf("%s: got %s, want %s", field, g, w))
+		}
+	}
+	return diffs, nil
+}
+
+// decodeJSONFields decodes a JSON object into its top-level fields, keeping
+// their values as raw JSON with insignificant whitespace removed.
+func decodeJSONFields(r io.Reader) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(raw))
+	for field, value := range raw {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, value); err != nil {
+			return nil, err
+		}
+		fields[field] = buf.String()
+	}
+	return fields, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestCompareResults tests field-level comparison of JSON results.
+func TestCompareResults(t *testing.T) {
+	result := &Result{ChainID: 1, Number: 7, StateRoot: common.Hash{0x01}, Valid: true}
+
+	tests := []struct {
+		expected string
+		skip     []string
+		want     []string
+	}{
+		{`{"chainId":1,"number":7,"hash":"0x0000000000000000000000000000000000000000000000000000000000000000","parentHash":"0x0000000000000000000000000000000000000000000000000000000000000000","gasUsed":0,"gasLimit":0,"txCount":0,"stateRoot":"0x0100000000000000000000000000000000000000000000000000000000000000","receiptRoot":"0x0000000000000000000000000000000000000000000000000000000000000000","expectedStateRoot":"0x0000000000000000000000000000000000000000000000000000000000000000","expectedReceiptRoot":"0x0000000000000000000000000000000000000000000000000000000000000000","valid":true,"exitCode":0}`, nil, nil},
+		{`{"chainId":1, "number":8, "gasUsed":0, "gasLimit":0, "txCount":0, "valid":true, "exitCode":0, "extra":true}`,
+			[]string{"hash", "parentHash", "stateRoot", "receiptRoot", "expectedStateRoot", "expectedReceiptRoot"},
+			[]string{"extra: missing, want true", "number: got 7, want 8"}},
+		{`{"chainId":1,"number":8,"valid":true}`,
+			[]string{"number", "hash", "parentHash", "gasUsed", "gasLimit", "txCount", "stateRoot", "receiptRoot", "expectedStateRoot", "expectedReceiptRoot"},
+			[]string{"exitCode: unexpected field with value 0"}},
+	}
+	for i, tt := range tests {
+		diffs, err := compareResults(result, []byte(tt.expected), tt.skip)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if strings.Join(diffs, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("test %d: diffs %q, want %q", i, diffs, tt.want)
+		}
+	}
+}
+
+// TestCompatCheck tests the compat-check subcommand against a golden result.
+func TestCompatCheck(t *testing.T) {
+	dir := t.TempDir()
+	payload := filepath.Join(dir, "payload.rlp")
+	if err := os.WriteFile(payload, makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expect := filepath.Join(dir, "expected.json")
+	f, err := os.Create(expect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	if err := writeResult(f, result); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if code := runCompatCheck([]string{"--payload", payload, "--expect", expect}); code != ExitSuccess {
+		t.Errorf("matching result: exit code %d", code)
+	}
+	if err := os.WriteFile(payload, makeEmptyPayload(t, result.StateRoot, common.Hash{}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := runCompatCheck([]string{"--payload", payload, "--expect", expect}); code != 1 {
+		t.Errorf("differing result: exit code %d, want 1", code)
+	}
+	if code := runCompatCheck([]string{"--payload", payload}); code != 2 {
+		t.Errorf("missing --expect: exit code %d, want 2", code)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// minHeaderFields is the number of fields in the oldest block header format,
+// used to recognise headers when diagnosing inputs that are not payloads.
+const minHeaderFields = 15
+
+// maxRLPDepth bounds the nesting of the lists descended into when diagnosing an
+// input. Payloads nest a handful of lists deep; the bound keeps inputs of
+// millions of nested lists from overflowing the stack, which is fatal rather
+// than a recoverable panic.
+const maxRLPDepth = 256
+
+// decodeRLP decodes the input into the payload, converting a panic raised while
+// decoding into an error, so that adversarial input is rejected rather than
+// crashing the keeper. The stack trace of the panic is logged at debug level.
+func decodeRLP(input []byte, payload *Payload) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			logger.Debug("Recovered from decoding panic", "err", value, "stack", string(debug.Stack()))
+			err = fmt.Errorf("decoding panicked: %v", value)
+		}
+	}()
+	decoded, err := stateless.DecodePayload(input)
+	if err != nil {
+		return err
+	}
+	*payload = *decoded
+	return nil
+}
+
+// describeDecodeError explains why the input could not be decoded as a payload.
+// Inputs that are not well-formed RLP are told apart from well-formed RLP of
+// the wrong structure, and common mistakes such as passing a bare block or
+// header instead of a payload are pointed out. Malformed RLP is located at the
+// offending value, structural mismatches at the start of the input.
+func describeDecodeError(input []byte, err error) *DecodeError {
+	if offset, wfErr := checkRLP(input); wfErr != nil {
+		return &DecodeError{Offset: offset, Reason: fmt.Sprintf("invalid RLP at offset %d: %v", offset, wfErr)}
+	}
+	elems, splitErr := rlp.SplitListValues(input)
+	if splitErr != nil {
+		return &DecodeError{Reason: fmt.Sprintf("valid RLP but not a payload: input is a string, expected a list [chainID, block, witness] (%v)", err)}
+	}
+	switch {
+	case isRLPList(elems, minHeaderFields) && len(elems) >= 3 && isRLPList(elems[1:2], 0):
+		return &DecodeError{Reason: fmt.Sprintf("valid RLP but not a payload: input looks like a bare block, expected a list [chainID, block, witness] (%v)", err)}
+	case len(elems) >= minHeaderFields && isHeaderLike(elems):
+		return &DecodeError{Reason: fmt.Sprintf("valid RLP but not a payload: input looks like a block header, expected a list [chainID, block, witness] (%v)", err)}
+	case len(elems) != 3:
+		return &DecodeError{Reason: fmt.Sprintf("valid RLP but not a payload: list of %d elements, expected [chainID, block, witness] (%v)", len(elems), err)}
+	default:
+		return &DecodeError{Reason: fmt.Sprintf("valid RLP but not a payload: %v", err)}
+	}
+}
+
+// checkRLP verifies that the input is exactly one well-formed RLP value,
+// descending into all nested lists up to maxRLPDepth. On failure, it returns
+// the offset of the offending value.
+func checkRLP(input []byte) (int, error) {
+	_, _, rest, err := rlp.Split(input)
+	if err != nil {
+		return 0, err
+	}
+	if len(rest) > 0 {
+		return len(input) - len(rest), fmt.Errorf("%d trailing bytes after value", len(rest))
+	}
+	return checkRLPValues(input, 0, 0)
+}
+
+// checkRLPValues verifies that the input, found at the given offset of the
+// whole input and nested in the given number of lists, is a sequence of
+// well-formed RLP values. On failure, it returns the offset of the offending
+// value.
+func checkRLPValues(input []byte, offset int, depth int) (int, error) {
+	for len(input) > 0 {
+		kind, content, rest, err := rlp.Split(input)
+		if err != nil {
+			return offset, err
+		}
+		if kind == rlp.List {
+			if depth == maxRLPDepth {
+				return offset, fmt.Errorf("lists nested more than %d levels deep", maxRLPDepth)
+			}
+			if at, err := checkRLPValues(content, offset+len(input)-len(rest)-len(content), depth+1); err != nil {
+				return at, err
+			}
+		}
+		offset += len(input) - len(rest)
+		input = rest
+	}
+	return offset, nil
+}
+
+// isRLPList reports whether the first of the encoded values is a list with at
+// least the given number of elements.
+func isRLPList(values [][]byte, minElems int) bool {
+	if len(values) == 0 {
+		return false
+	}
+	content, _, err := rlp.SplitList(values[0])
+	if err != nil {
+		return false
+	}
+	n, err := rlp.CountValues(content)
+	return err == nil && n >= minElems
+}
+
+// isHeaderLike reports whether the encoded list elements start like a block
+// header, with the parent hash, uncle hash and coinbase.
+func isHeaderLike(elems [][]byte) bool {
+	sizes := []int{common.HashLength, common.HashLength, common.AddressLength}
+	for i, size := range sizes {
+		content, _, err := rlp.SplitString(elems[i])
+		if err != nil || len(content) != size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestDescribeDecodeError tests that inputs which are not payloads are reported
+// according to what they look like.
+func TestDescribeDecodeError(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	block, _ := rlp.EncodeToBytes(types.NewBlock(header, nil, nil, trie.NewStackTrie(nil)))
+	headerRLP, _ := rlp.EncodeToBytes(header)
+	pair, _ := rlp.EncodeToBytes([]uint64{1, 2})
+
+	tests := []struct {
+		input []byte
+		want  string
+	}{
+		{[]byte{0xc3, 0x01}, "invalid RLP"},
+		{[]byte{0xc2, 0xc3, 0x01}, "invalid RLP"},
+		{block, "looks like a bare block"},
+		{headerRLP, "looks like a block header"},
+		{pair, "list of 2 elements"},
+	}
+	for i, tt := range tests {
+		result := process(tt.input)
+		if result.ExitCode != ExitDecodeFailed {
+			t.Errorf("test %d: exit code = %d, want %d", i, result.ExitCode, ExitDecodeFailed)
+		}
+		if !strings.Contains(result.Error, tt.want) {
+			t.Errorf("test %d: error %q does not mention %q", i, result.Error, tt.want)
+		}
+	}
+}
+
+// TestTrailingBytes tests that bytes following an otherwise well-formed payload
+// are reported with a dedicated exit code.
+func TestTrailingBytes(t *testing.T) {
+	input := append(makeGasPayload(t, 1, 0), 0xc0, 0x80)
+
+	result := process(input)
+	if result.ExitCode != ExitTrailingBytes {
+		t.Fatalf("exit code = %d, want %d", result.ExitCode, ExitTrailingBytes)
+	}
+	if !strings.Contains(result.Error, "2 trailing bytes") {
+		t.Errorf("error %q does not report the trailing byte count", result.Error)
+	}
+}
+
+// TestDecodeErrorOffset tests that decode errors locate the offending value.
+func TestDecodeErrorOffset(t *testing.T) {
+	tests := []struct {
+		input  []byte
+		offset int
+	}{
+		{[]byte{0xc3, 0x01}, 0},
+		{[]byte{0xc4, 0x01, 0xc2, 0x81, 0x00}, 3},
+		{append(makeGasPayload(t, 1, 0), 0xc0, 0x80), len(makeGasPayload(t, 1, 0))},
+		{[]byte{0xc2, 0x01, 0x02}, 0},
+	}
+	for i, tt := range tests {
+		var decodeErr *DecodeError
+		if _, result := decodePayload(tt.input); !errors.As(result.err, &decodeErr) {
+			t.Errorf("test %d: error %v is not a decode error", i, result.err)
+		} else if decodeErr.Offset != tt.offset {
+			t.Errorf("test %d: offset = %d, want %d", i, decodeErr.Offset, tt.offset)
+		}
+	}
+}
+
+// nestLists encodes the given number of lists nested in one another, the
+// innermost one empty.
+func nestLists(depth int) []byte {
+	// Lists are wrapped inside out, so the encoding is built back to front.
+	var reversed []byte
+	for i := 0; i < depth; i++ {
+		size := len(reversed)
+		if size < 56 {
+			reversed = append(reversed, byte(0xc0+size))
+			continue
+		}
+		var length int
+		for ; size > 0; size >>= 8 {
+			reversed = append(reversed, byte(size))
+			length++
+		}
+		reversed = append(reversed, byte(0xf7+length))
+	}
+	slices.Reverse(reversed)
+	return reversed
+}
+
+// TestDeeplyNestedInput tests that deeply nested lists are rejected as invalid
+// payloads without descending into all of them.
+func TestDeeplyNestedInput(t *testing.T) {
+	if offset, err := checkRLP(nestLists(maxRLPDepth)); err != nil {
+		t.Fatalf("nesting of %d lists rejected at offset %d: %v", maxRLPDepth, offset, err)
+	}
+	if _, err := checkRLP(nestLists(maxRLPDepth + 1)); err == nil {
+		t.Fatalf("nesting of %d lists accepted", maxRLPDepth+1)
+	}
+	result := process(nestLists(1_000_000))
+	if result.ExitCode != ExitDecodeFailed {
+		t.Fatalf("exit code = %d, want %d", result.ExitCode, ExitDecodeFailed)
+	}
+	if !strings.Contains(result.Error, "nested more than") {
+		t.Errorf("error %q does not report the nesting", result.Error)
+	}
+}
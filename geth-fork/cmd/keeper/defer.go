@@ -0,0 +1,62 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// peekHeader decodes the chain ID and block header of a payload, without
+// decoding the transactions and witness that make up the bulk of it.
+func peekHeader(input []byte) (uint64, *types.Header, error) {
+	content, _, err := rlp.SplitList(input)
+	if err != nil {
+		return 0, nil, err
+	}
+	chainID, rest, err := rlp.SplitUint64(content)
+	if err != nil {
+		return 0, nil, err
+	}
+	block, _, err := rlp.SplitList(rest)
+	if err != nil {
+		return 0, nil, err
+	}
+	_, _, rest, err = rlp.Split(block)
+	if err != nil {
+		return 0, nil, err
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(block[:len(block)-len(rest)], header); err != nil {
+		return 0, nil, err
+	}
+	return chainID, header, nil
+}
+
+// deferredResult creates the result of a payload whose validation is deferred
+// because its block is too expensive to prove.
+func deferredResult(chainID uint64, header *types.Header) *Result {
+	return &Result{
+		ChainID:    chainID,
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		GasUsed:    header.GasUsed,
+		Deferred:   true,
+		Build:      currentBuild(),
+	}
+}
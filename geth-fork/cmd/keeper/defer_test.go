@@ -0,0 +1,89 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// makeGasPayload creates a payload for a block using the given amount of gas.
+func makeGasPayload(t *testing.T, number int64, gasUsed uint64) []byte {
+	header := &types.Header{Number: big.NewInt(number), Difficulty: big.NewInt(0), GasUsed: gasUsed}
+	witness, _ := stateless.NewWitness(header, nil)
+	witness.Headers = []*types.Header{{Number: big.NewInt(number - 1), Difficulty: big.NewInt(0)}}
+	block := types.NewBlock(header, nil, nil, trie.NewStackTrie(nil))
+	input, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: block, Witness: witness})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+// TestPeekHeader tests that the header of a payload can be decoded on its own.
+func TestPeekHeader(t *testing.T) {
+	chainID, header, err := peekHeader(makeGasPayload(t, 7, 1234))
+	if err != nil {
+		t.Fatalf("peekHeader failed: %v", err)
+	}
+	if chainID != 1 || header.Number.Uint64() != 7 || header.GasUsed != 1234 {
+		t.Errorf("unexpected chain %d header %d gas %d", chainID, header.Number, header.GasUsed)
+	}
+	if _, _, err := peekHeader([]byte{0xc1, 0x01}); err == nil {
+		t.Error("expected error for payload without block")
+	}
+}
+
+// TestBatchDeferAboveGas tests that expensive blocks are recorded as deferred
+// rather than validated or counted as failures.
+func TestBatchDeferAboveGas(t *testing.T) {
+	dir := t.TempDir()
+	deferred := filepath.Join(dir, "deferred.ndjson")
+	batch := makeBatch(makeGasPayload(t, 1, 100), makeGasPayload(t, 2, 200))
+
+	config := &batchConfig{outdir: dir, deferGas: 150, deferOutput: deferred}
+	if code := runBatch(batch, config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	blob, err := os.ReadFile(deferred)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record Result
+	if err := json.Unmarshal(blob, &record); err != nil || bytes.Count(blob, []byte("\n")) != 1 {
+		t.Fatalf("unexpected deferred output %q: %v", blob, err)
+	}
+	if !record.Deferred || record.Number != 2 || record.GasUsed != 200 {
+		t.Errorf("unexpected deferred record %+v", record)
+	}
+	// Once the failing block is resolved, deferral alone does not fail the batch.
+	if err := writeResultFile(dir, 0, &Result{Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if code := runBatch(batch, config); code != ExitSuccess {
+		t.Errorf("resumed exit code = %d, want %d", code, ExitSuccess)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// verifyDifficulty recomputes the difficulty of a proof-of-work block from its
+// parent header, using the difficulty adjustment algorithm of the fork the
+// block belongs to, and compares it with the declared one. Proof-of-stake
+// blocks, which carry no difficulty, are not checked. Clique blocks only have
+// to declare the in-turn or out-of-turn difficulty, since which of the two is
+// right depends on the signer set that keeper does not track.
+func verifyDifficulty(config *params.ChainConfig, header, parent *types.Header) error {
+	if parent == nil {
+		return errors.New("witness has no parent header")
+	}
+	if parent.Hash() != header.ParentHash {
+		return fmt.Errorf("witness parent header %x does not match parent hash %x", parent.Hash(), header.ParentHash)
+	}
+	if header.Difficulty == nil {
+		return errors.New("header has no difficulty")
+	}
+	if header.Difficulty.Sign() == 0 && config.IsLondon(header.Number) {
+		return nil
+	}
+	if config.Clique != nil {
+		if header.Difficulty.Cmp(common.Big1) != 0 && header.Difficulty.Cmp(common.Big2) != 0 {
+			return fmt.Errorf("invalid clique difficulty %v (expected 1 or 2)", header.Difficulty)
+		}
+		return nil
+	}
+	if want := ethash.CalcDifficulty(config, header.Time, parent); header.Difficulty.Cmp(want) != 0 {
+		return fmt.Errorf("difficulty mismatch (header: %v expected: %v)", header.Difficulty, want)
+	}
+	return nil
+}
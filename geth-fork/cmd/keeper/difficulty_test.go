@@ -0,0 +1,65 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestVerifyDifficulty tests the difficulty check of proof-of-work blocks
+// against mainnet block 1, of proof-of-stake blocks and of clique blocks.
+func TestVerifyDifficulty(t *testing.T) {
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(17_179_869_184), UncleHash: types.EmptyUncleHash}
+	block := func(difficulty int64) *types.Header {
+		return &types.Header{Number: big.NewInt(1), Time: 1438269988, Difficulty: big.NewInt(difficulty), ParentHash: genesis.Hash()}
+	}
+	tests := []struct {
+		header  *types.Header
+		parent  *types.Header
+		wantErr string
+	}{
+		// Block 1 of mainnet, mined over a second after the genesis timestamp
+		{block(17_171_480_576), genesis, ""},
+		{block(17_171_480_577), genesis, "difficulty mismatch"},
+		{block(17_171_480_576), nil, "no parent header"},
+		{block(17_171_480_576), block(0), "does not match parent hash"},
+		// Proof-of-stake blocks carry no difficulty
+		{&types.Header{Number: big.NewInt(20_000_000), Difficulty: big.NewInt(0), ParentHash: genesis.Hash()}, genesis, ""},
+	}
+	for i, tt := range tests {
+		err := verifyDifficulty(params.MainnetChainConfig, tt.header, tt.parent)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("test %d: error %v, want %q", i, err, tt.wantErr)
+		}
+	}
+	// Clique blocks are either in turn (2) or out of turn (1)
+	clique := &params.ChainConfig{ChainID: big.NewInt(1337), Clique: &params.CliqueConfig{Period: 15, Epoch: 30000}}
+	for difficulty, wantErr := range map[int64]bool{0: true, 1: false, 2: false, 3: true} {
+		err := verifyDifficulty(clique, block(difficulty), genesis)
+		if wantErr != (err != nil) {
+			t.Errorf("clique difficulty %d: error %v, want error %v", difficulty, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runDiffRoots runs the diff-roots subcommand: it validates two payloads, such
+// as two witnesses for the same block, and reports whether execution computes
+// the same state and receipt roots for both. It exits with 0 if the roots are
+// identical and 1 if they differ. If a payload cannot be executed, its exit
+// code is returned instead.
+func runDiffRoots(args []string, out io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Error: diff-roots requires two payload files")
+		fmt.Fprintln(os.Stderr, "Usage: diff-roots <a> <b>")
+		return 2
+	}
+	var results [2]*Result
+	for i, path := range args {
+		raw, err := readInputFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read payload: %v\n", err)
+			return ExitInvalidInput
+		}
+		input, _, err := decodeInput(raw, *inputFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: input decoding failed: %v\n", path, err)
+			return ExitInvalidInput
+		}
+		result := process(input)
+		if result.StateRoot == (common.Hash{}) {
+			fmt.Fprintf(os.Stderr, "%s: execution did not complete: %s\n", path, result.errorMessage())
+			return result.ExitCode
+		}
+		results[i] = result
+	}
+	a, b := results[0], results[1]
+	if a.Hash != b.Hash {
+		fmt.Fprintf(os.Stderr, "warning: payloads are for different blocks (%d %s, %d %s)\n", a.Number, a.Hash.Hex(), b.Number, b.Hash.Hex())
+	}
+	fmt.Fprintf(out, "state root:   %x %x %s\n", a.StateRoot, b.StateRoot, matchString(a.StateRoot == b.StateRoot))
+	fmt.Fprintf(out, "receipt root: %x %x %s\n", a.ReceiptRoot, b.ReceiptRoot, matchString(a.ReceiptRoot == b.ReceiptRoot))
+	if a.StateRoot != b.StateRoot || a.ReceiptRoot != b.ReceiptRoot {
+		return 1
+	}
+	return ExitSuccess
+}
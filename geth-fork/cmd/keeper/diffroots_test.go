@@ -0,0 +1,76 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestDiffRoots tests the comparison of the roots computed for two payloads.
+func TestDiffRoots(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, payload []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, payload, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	// A block rewarding another coinbase computes a different state root.
+	parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: types.EmptyRootHash}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash(), Coinbase: common.Address{0xcb}, TxHash: types.EmptyTxsHash, ReceiptHash: types.EmptyReceiptsHash}
+	witness, _ := stateless.NewWitness(header, nil)
+	witness.Headers = []*types.Header{parent}
+	other, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: types.NewBlockWithHeader(header), Witness: witness})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := write("a.rlp", makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	b := write("b.rlp", makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	c := write("c.rlp", other)
+	bad := write("bad.rlp", []byte{0x05})
+
+	tests := []struct {
+		args []string
+		code int
+		want string
+	}{
+		{[]string{a, b}, ExitSuccess, "(match)"},
+		{[]string{a, c}, 1, "(MISMATCH)"},
+		{[]string{a, bad}, ExitInvalidInput, ""},
+		{[]string{a}, 2, ""},
+	}
+	for i, tt := range tests {
+		var out bytes.Buffer
+		if code := runDiffRoots(tt.args, &out); code != tt.code {
+			t.Errorf("test %d: exit code %d, want %d", i, code, tt.code)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("test %d: output %q does not contain %q", i, out.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// verifyEmptyTxRoot checks that a block without transactions commits to the
+// empty transaction trie. Blocks with transactions are not checked.
+func verifyEmptyTxRoot(block *types.Block) error {
+	if len(block.Transactions()) > 0 {
+		return nil
+	}
+	if root := block.TxHash(); root != types.EmptyTxsHash {
+		return fmt.Errorf("empty block has transaction root %x, want the empty trie root %x", root, types.EmptyTxsHash)
+	}
+	return nil
+}
+
+// verifyEmptyReceiptRoot checks that a block without transactions commits to
+// the empty receipt trie, which would otherwise only be noticed after executing
+// the block. The mismatch is reported as the ReceiptRootMismatchError execution
+// would have ended with. Blocks with transactions are not checked.
+func verifyEmptyReceiptRoot(block *types.Block) error {
+	if len(block.Transactions()) > 0 {
+		return nil
+	}
+	if root := block.ReceiptHash(); root != types.EmptyReceiptsHash {
+		return fmt.Errorf("empty block does not commit to the empty receipt trie: %w", &ReceiptRootMismatchError{Expected: root, Actual: types.EmptyReceiptsHash})
+	}
+	return nil
+}
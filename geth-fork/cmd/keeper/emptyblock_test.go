@@ -0,0 +1,55 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestEmptyBlockRoots tests that empty blocks declaring other than the empty
+// transaction and receipt roots fail before execution.
+func TestEmptyBlockRoots(t *testing.T) {
+	stateRoot := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)).StateRoot
+
+	parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: types.EmptyRootHash}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash(), Root: stateRoot, TxHash: common.Hash{0x01}, ReceiptHash: types.EmptyReceiptsHash}
+	witness, _ := stateless.NewWitness(header, nil)
+	witness.Headers = []*types.Header{parent}
+	badTxRoot, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: types.NewBlockWithHeader(header), Witness: witness})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := process(badTxRoot); result.Stage != stageValidate || result.ExitCode != ExitHeaderInconsistent {
+		t.Errorf("bad transaction root: stage %q exit code %d, want %q %d", result.Stage, result.ExitCode, stageValidate, ExitHeaderInconsistent)
+	}
+	result := process(makeEmptyPayload(t, stateRoot, common.Hash{0x01}))
+	if result.Stage != stageValidate || result.ExitCode != ExitReceiptRootMismatch || result.StateRoot != (common.Hash{}) {
+		t.Errorf("bad receipt root: stage %q exit code %d, want %q %d without execution", result.Stage, result.ExitCode, stageValidate, ExitReceiptRootMismatch)
+	}
+	// Skipping the receipt root comparison skips the fast path as well.
+	defer func(skip bool) { *skipReceiptRoot = skip }(*skipReceiptRoot)
+	*skipReceiptRoot = true
+	if result := process(makeEmptyPayload(t, stateRoot, common.Hash{0x01})); !result.Valid {
+		t.Errorf("bad receipt root not skipped: %+v", result)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DecodeError is returned when the input is not a well-formed payload.
+type DecodeError struct {
+	Offset int    // Position in the input of the value that could not be decoded
+	Reason string // Description of what is wrong with the input
+}
+
+func (e *DecodeError) Error() string {
+	return e.Reason
+}
+
+// ValidationError is returned when a decoded payload is semantically invalid.
+type ValidationError struct {
+	msg string
+}
+
+func (e *ValidationError) Error() string {
+	return e.msg
+}
+
+// UnknownChainIDError is returned when the payload targets a chain the keeper
+// has no configuration for.
+type UnknownChainIDError struct {
+	ChainID uint64
+}
+
+func (e *UnknownChainIDError) Error() string {
+	return fmt.Sprintf("unsupported chain ID: %d", e.ChainID)
+}
+
+// StateRootMismatchError is returned when the state root computed by executing
+// the block differs from the one in its header.
+type StateRootMismatchError struct {
+	Expected common.Hash // State root declared in the block header
+	Actual   common.Hash // State root computed by execution
+}
+
+func (e *StateRootMismatchError) Error() string {
+	return fmt.Sprintf("stateless self-validation root mismatch (cross: %x local: %x)", e.Actual, e.Expected)
+}
+
+// ReceiptRootMismatchError is returned when the receipt root computed by
+// executing the block differs from the one in its header.
+type ReceiptRootMismatchError struct {
+	Expected common.Hash // Receipt root declared in the block header
+	Actual   common.Hash // Receipt root computed by execution
+}
+
+func (e *ReceiptRootMismatchError) Error() string {
+	return fmt.Sprintf("stateless self-validation receipt root mismatch (cross: %x local: %x)", e.Actual, e.Expected)
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params/forks"
+)
+
+var (
+	inputTar           = flag.String("input-tar", "", "validate every file of this gzipped tar archive as a payload, reporting results by member name")
+	batchMode          = flag.Bool("batch", false, "validate a stream of payloads, each prefixed by its 4 byte big-endian length")
+	partialBatchOutput = flag.String("partial-batch-output", "", "directory to write each batch result to as soon as it completes; existing results are skipped on restart")
+	sampleRate         = flag.Float64("sample", 0, "fraction of batch payloads to validate, selected deterministically from the seed (0 = all)")
+	sampleSeed         = flag.String("seed", "", "seed for batch sampling (default: derived from the batch payloads)")
+	sinceBlock         = flag.Uint64("since-block", 0, "skip batch payloads whose block number is at or below this one, to resume a forward backfill (0 = none)")
+	checkpointFile     = flag.String("checkpoint", "", "file recording the last processed batch payload, durably updated after each one; a restarted run continues after it")
+	reverseBatch       = flag.Bool("reverse", false, "validate the payloads of a batch from last to first")
+	chainContinuity    = flag.Bool("chain-continuity", false, "check that consecutive batch payloads form a chain of parent hashes")
+	outputFormat       = flag.String("output", "text", "format of the validation result written to stdout (text or json)")
+	outputTemplate     = flag.String("output-template", "", "Go text/template rendered against each result and written to stdout, overrides --output")
+	metricsFile        = flag.String("metrics-file", "", "file to write validation metrics and stage timings to in the Prometheus text format, rewritten after every payload")
+	outputInclude      = flag.String("output-include", "", "comma-separated list of the only fields to include in the JSON result")
+	outputExclude      = flag.String("output-exclude", "", "comma-separated list of fields to leave out of the JSON result")
+	outputAppend       = flag.String("output-append", "", "append the JSON result to this file, one line per payload")
+	resultFD           = flag.Int("result-fd", -1, "open file descriptor to write the JSON result line to, e.g. 3, keeping stdout and stderr as they are (-1 = none)")
+	deferAboveGas      = flag.Uint64("defer-above-gas", 0, "defer batch payloads whose block uses more gas than this instead of validating them (0 = never)")
+	deferredOutput     = flag.String("deferred-output", "", "file to record deferred batch payloads in, one JSON line per block")
+	twoPhase           = flag.Bool("two-phase", false, "decode all batch payloads before executing any, and execute none if one fails to decode")
+	skipStateRoot      = flag.Bool("skip-state-root", false, "execute the block but do not compare the computed state root with the header")
+	skipReceiptRoot    = flag.Bool("skip-receipt-root", false, "execute the block but do not compare the computed receipt root with the header")
+	continueOnMismatch = flag.Bool("continue-on-mismatch", false, "run every commitment check even after one fails, reporting all mismatches; the first one determines the exit code")
+	reportAll          = flag.Bool("report-all", false, "run every structural check even after one fails, reporting all findings; the first one determines the exit code")
+	logLevel           = flag.String("log-level", "info", "minimum level of log messages written to stderr: debug, info, warn or error")
+	logFormat          = flag.String("log-format", logFormatText, "format of log messages written to stderr: text or json")
+	quiet              = flag.Bool("quiet", false, "write nothing to stdout or stderr, reporting the outcome through the exit code only")
+	countKeccak        = flag.Bool("count-keccak", false, "count the Keccak256 hashes computed while validating the payload and the bytes hashed, to estimate its proving cost")
+	errorsOnly         = flag.Bool("errors-only", false, "print only the results of failed batch payloads; the summary still counts every payload")
+	parallel           = flag.Int("parallel", 1, "number of batch payloads to validate concurrently, each by its own worker; results are reported in order")
+	prefetchDepth      = flag.Int("prefetch", 0, "number of batch payloads to decode in the background ahead of validation (0 = none)")
+	chainConfigPath    = flag.String("chain-config", "", "genesis JSON file to take the chain configuration from, replacing the built-in networks")
+	decodeOnly         = flag.Bool("decode-only", false, "only decode and structurally validate the payload, without executing the block")
+	maxWitnessSize     = flag.Uint64("max-witness-size", 0, "maximum size in bytes of the decoded witness (0 = unlimited)")
+	maxWitnessNodes    = flag.Uint64("max-witness-nodes", 0, "maximum number of trie nodes in the decoded witness (0 = unlimited)")
+	maxTxs             = flag.Uint64("max-txs", 0, "maximum number of transactions in the block (0 = unlimited)")
+	verifyCodes        = flag.Bool("verify-witness-codes", false, "check each witness bytecode against the code hashes of the witness accounts before decoding the payload")
+	gcPercent          = flag.Int("gc-percent", -1, "garbage collection target percentage; -1 disables collection for the lowest latency at the cost of memory growing with every allocation, 100 bounds memory in long batch runs")
+	keccakBackend      = flag.String("keccak-backend", "", "Keccak256 implementation to hash with: standard, portable, or ziren in ziren builds (default: the first listed by the version command)")
+	memoryPerPayload   = flag.Uint64("limit-memory-per-payload", 0, "in batch mode, fail payloads using more than this many bytes of memory without stopping the batch (0 = unlimited)")
+	maxMemory          = flag.Uint64("max-memory", 0, "fail with ExitResourceExhausted before decoding or execution if the process is projected to use more than this many bytes of memory (0 = unlimited)")
+	aggressiveFree     = flag.Bool("aggressive-free", false, "in batch mode, drop each payload once its result is emitted and return the memory to the OS before the next one")
+	precomputeHashes   = flag.Bool("precompute-hashes", false, "compute the block and transaction hashes up front, in parallel, before validation")
+	inputPath          = flag.String("input", "", "file to read the payload from, - for stdin (default: platform input)")
+	maxInputSize       = flag.Int("max-input-size", MaxInputSize, "maximum size of a payload in bytes, also read from $KEEPER_MAX_INPUT_SIZE")
+	inputFormat        = flag.String("format", formatRaw, "encoding of the input payload (raw, hex, base64, gzip, zstd or auto)")
+	execTimeout        = flag.Duration("timeout", 0, "abort block execution that takes longer than this, e.g. 5s (0 = unbounded)")
+	diffReceiptsFile   = flag.String("diff-receipts", "", "on a receipt root mismatch, compare the computed receipts field by field with the expected ones in this JSON file, as returned by eth_getBlockReceipts")
+	traceFile          = flag.String("trace", "", "write a JSON trace of every transaction of the block, opcode by opcode, to this file")
+	heartbeat          = flag.Duration("heartbeat", 0, "log a progress line at this interval while a block executes, e.g. 5s (0 = never)")
+	genesisAlloc       = flag.String("genesis-alloc", "", "execute against the pre-state built from this genesis allocation JSON instead of the witness state, for synthetic tests")
+	stateSnapshot      = flag.String("state-snapshot", "", "execute against this full pre-state (geth dump JSON) and cross-check the witness")
+	emitReceipt        = flag.String("emit-receipt", "", "write a signed receipt attesting the validation to this file")
+	signingKey         = flag.String("signing-key", "", "file holding the hex-encoded secp256k1 private key to sign receipts with")
+	strict             = flag.Bool("strict", false, "check the withdrawals list of the block against the withdrawals root of its header before execution")
+	checkDifficulty    = flag.Bool("check-difficulty", false, "verify the difficulty of proof-of-work blocks against the one computed from the parent header")
+	txHashes           = flag.Bool("tx-hashes", false, "report the hashes of the block's transactions in the JSON result")
+	accessedAddresses  = flag.Bool("accessed-addresses", false, "report the distinct accounts accessed by the block's transactions in the JSON result")
+	checkAccessLists   = flag.Bool("check-access-lists", false, "compare declared EIP-2930 access lists against executed accesses and the witness")
+	checkSystemCalls   = flag.Bool("check-system-calls", false, "verify the system contract storage left behind by the block's system calls (EIP-4788, EIP-2935, EIP-7002, EIP-7251)")
+	captureReverts     = flag.Bool("capture-reverts", false, "report the revert reason of every failed transaction in the block")
+)
+
+// requiredFork is the fork set with --require-fork-activated, nil if unset.
+var requiredFork *forks.Fork
+
+// atBlock is the block number set with --at-block, nil if unset.
+var atBlock *uint64
+
+// expectedBlockHash is the block hash set with --expect-block-hash, nil if
+// unset.
+var expectedBlockHash *common.Hash
+
+func init() {
+	flag.Func("require-fork-activated", "fail blocks for which the given `fork` (e.g. Paris) is not yet active", func(name string) error {
+		fork, err := parseFork(name)
+		if err != nil {
+			return err
+		}
+		requiredFork = &fork
+		return nil
+	})
+	flag.Func("at-block", "evaluate the block-numbered forks of the chain config as if the block had the given `number`", func(value string) error {
+		number, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		atBlock = &number
+		return nil
+	})
+	flag.Func("expect-block-hash", "fail unless the decoded block has the given `hash`, e.g. one confirmed by the consensus layer", func(value string) error {
+		blob, err := hexutil.Decode(value)
+		if err != nil {
+			return err
+		}
+		if len(blob) != common.HashLength {
+			return fmt.Errorf("hash must be %d bytes, got %d", common.HashLength, len(blob))
+		}
+		hash := common.BytesToHash(blob)
+		expectedBlockHash = &hash
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "[flags] [command | file]")
+		flag.PrintDefaults()
+		fmt.Fprintln(os.Stderr, `
+Validates the stateless execution of an RLP-encoded payload containing
+a chain ID, a block and its execution witness.
+
+The payload is the RLP list [chain ID, block, witness], with the block in
+its consensus encoding and the witness as [headers, codes, state, keys].
+It is read from the given file, or from stdin for "-", either as raw RLP
+or encoded as hex, base64, gzip or zstd (see --format).
+
+Commands:
+  repl [file]   explore a payload interactively
+  compat-check --payload <file> --expect <json>
+                validate a payload and compare the JSON result with an expected one
+  version       print the build information and the built-in networks
+  diff-roots <a> <b>
+                validate two payloads and compare the state and receipt roots they compute
+  serve <socket>
+                validate payloads sent over a Unix domain socket until terminated
+  keccak [--512] [file...]
+                print the Keccak256 or Keccak512 digest of each file, or of stdin
+  self-test     validate the bundled Hoodi block to check the binary on this host`)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/forks"
+)
+
+// activeFork returns the fork whose rules apply to the given block. Forks after
+// the merge are scheduled by timestamp, earlier ones by block number. A block
+// counts as post-merge if the chain config says so or if it carries no
+// proof-of-work difficulty, which covers networks that merged by total
+// difficulty rather than at a configured block.
+func activeFork(config *params.ChainConfig, header *types.Header) forks.Fork {
+	if config.IsLondon(header.Number) {
+		if config.IsPostMerge(header.Number.Uint64(), header.Time) || header.Difficulty == nil || header.Difficulty.Sign() == 0 {
+			return config.LatestFork(header.Time)
+		}
+	}
+	blockForks := []struct {
+		fork   forks.Fork
+		active func(*big.Int) bool
+	}{
+		{forks.GrayGlacier, config.IsGrayGlacier},
+		{forks.ArrowGlacier, config.IsArrowGlacier},
+		{forks.London, config.IsLondon},
+		{forks.Berlin, config.IsBerlin},
+		{forks.MuirGlacier, config.IsMuirGlacier},
+		{forks.Istanbul, config.IsIstanbul},
+		{forks.Petersburg, config.IsPetersburg},
+		{forks.Constantinople, config.IsConstantinople},
+		{forks.Byzantium, config.IsByzantium},
+		{forks.SpuriousDragon, config.IsEIP158},
+		{forks.TangerineWhistle, config.IsEIP150},
+		{forks.DAO, config.IsDAOFork},
+		{forks.Homestead, config.IsHomestead},
+	}
+	for _, f := range blockForks {
+		if f.active(header.Number) {
+			return f.fork
+		}
+	}
+	return forks.Frontier
+}
+
+// configAtBlock returns a copy of the chain config in which the forks scheduled
+// by block number are active for the block of the given number exactly if they
+// are active at the block number to evaluate them at: active forks are moved
+// to genesis and inactive ones after the block. Forks scheduled by timestamp
+// are left as they are. The DAO fork, which changes the state in its very
+// block, is moved onto the block if it is to be evaluated at the fork block.
+func configAtBlock(config *params.ChainConfig, number *big.Int, at uint64) *params.ChainConfig {
+	cpy := *config
+	evaluated := new(big.Int).SetUint64(at)
+	after := new(big.Int).Add(number, common.Big1)
+
+	// Inactive forks are not unset, as some forks default to an earlier one
+	// when unset.
+	for _, activation := range []**big.Int{
+		&cpy.HomesteadBlock, &cpy.EIP150Block, &cpy.EIP155Block, &cpy.EIP158Block,
+		&cpy.ByzantiumBlock, &cpy.ConstantinopleBlock, &cpy.PetersburgBlock, &cpy.IstanbulBlock,
+		&cpy.MuirGlacierBlock, &cpy.BerlinBlock, &cpy.LondonBlock, &cpy.ArrowGlacierBlock,
+		&cpy.GrayGlacierBlock, &cpy.MergeNetsplitBlock,
+	} {
+		if *activation == nil {
+			continue
+		}
+		if (*activation).Cmp(evaluated) <= 0 {
+			*activation = common.Big0
+		} else {
+			*activation = after
+		}
+	}
+	if cpy.DAOForkBlock != nil {
+		switch cpy.DAOForkBlock.Cmp(evaluated) {
+		case 0:
+			cpy.DAOForkBlock = new(big.Int).Set(number)
+		case -1:
+			cpy.DAOForkBlock = common.Big0
+		default:
+			cpy.DAOForkBlock = after
+		}
+	}
+	return &cpy
+}
+
+// parseFork returns the fork with the given name. Names are matched ignoring
+// case and spaces, so both "Gray Glacier" and "grayglacier" are accepted.
+func parseFork(name string) (forks.Fork, error) {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	}
+	for fork := forks.Frontier; fork <= forks.Amsterdam; fork++ {
+		if normalize(fork.String()) == normalize(name) {
+			return fork, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown fork %q", name)
+}
+
+// verifyForkFields checks that the optional header fields introduced by forks
+// are present exactly when the chain config activates those forks for the
+// block. A mismatch means the block was built for another chain or fork
+// schedule than the one selected by the payload's chain ID, which would
+// otherwise only surface as an obscure failure during execution.
+func verifyForkFields(config *params.ChainConfig, header *types.Header) error {
+	fields := []struct {
+		name    string
+		present bool
+		active  bool
+		fork    forks.Fork
+	}{
+		{"base fee", header.BaseFee != nil, config.IsLondon(header.Number), forks.London},
+		{"withdrawals root", header.WithdrawalsHash != nil, config.IsShanghai(header.Number, header.Time), forks.Shanghai},
+		{"blob gas used", header.BlobGasUsed != nil, config.IsCancun(header.Number, header.Time), forks.Cancun},
+		{"excess blob gas", header.ExcessBlobGas != nil, config.IsCancun(header.Number, header.Time), forks.Cancun},
+		{"parent beacon root", header.ParentBeaconRoot != nil, config.IsCancun(header.Number, header.Time), forks.Cancun},
+		{"requests hash", header.RequestsHash != nil, config.IsPrague(header.Number, header.Time), forks.Prague},
+	}
+	for _, field := range fields {
+		switch {
+		case field.active && !field.present:
+			return fmt.Errorf("header lacks %s, but %v is active for block %d on chain %v", field.name, field.fork, header.Number, config.ChainID)
+		case !field.active && field.present:
+			return fmt.Errorf("header has %s, but %v is not active for block %d on chain %v", field.name, field.fork, header.Number, config.ChainID)
+		}
+	}
+	// Blocks after the merge carry no proof-of-work difficulty. The merge block
+	// itself is not scheduled by the config on all networks, so only blocks of
+	// later forks are checked.
+	if config.IsShanghai(header.Number, header.Time) && header.Difficulty != nil && header.Difficulty.Sign() != 0 {
+		return fmt.Errorf("header has difficulty %v, but block %d on chain %v is after the merge", header.Difficulty, header.Number, config.ChainID)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/forks"
+)
+
+// TestActiveFork tests the fork reported for blocks across the mainnet history.
+func TestActiveFork(t *testing.T) {
+	tests := []struct {
+		number     uint64
+		time       uint64
+		difficulty int64
+		want       forks.Fork
+	}{
+		{1, 1438269988, 1, forks.Frontier},
+		{1_150_000, 1457981393, 1, forks.Homestead},
+		{4_370_000, 1508131331, 1, forks.Byzantium},
+		{12_965_000, 1628166822, 1, forks.London},
+		{15_537_393, 1663224162, 1, forks.GrayGlacier},
+		{15_537_394, 1663224179, 0, forks.Paris},
+		{17_034_870, 1681338455, 0, forks.Shanghai},
+		{19_426_587, 1710338135, 0, forks.Cancun},
+		{22_431_084, 1746612311, 0, forks.Prague},
+	}
+	for _, tt := range tests {
+		header := &types.Header{
+			Number:     new(big.Int).SetUint64(tt.number),
+			Time:       tt.time,
+			Difficulty: big.NewInt(tt.difficulty),
+		}
+		if fork := activeFork(params.MainnetChainConfig, header); fork != tt.want {
+			t.Errorf("block %d: active fork %v, want %v", tt.number, fork, tt.want)
+		}
+	}
+}
+
+// TestParseFork tests that fork names are parsed regardless of case and spaces.
+func TestParseFork(t *testing.T) {
+	for name, want := range map[string]forks.Fork{
+		"Frontier":     forks.Frontier,
+		"paris":        forks.Paris,
+		"Gray Glacier": forks.GrayGlacier,
+		"grayglacier":  forks.GrayGlacier,
+		"PRAGUE":       forks.Prague,
+	} {
+		if fork, err := parseFork(name); err != nil || fork != want {
+			t.Errorf("parseFork(%q) = %v, %v, want %v", name, fork, err, want)
+		}
+	}
+	if _, err := parseFork("merge"); err == nil {
+		t.Error("expected error for unknown fork")
+	}
+}
+
+// TestVerifyForkFields tests that header fields must match the forks active for
+// the block according to the chain config.
+func TestVerifyForkFields(t *testing.T) {
+	var (
+		hash   = common.Hash{0x01}
+		zero   = uint64(0)
+		cancun = func() *types.Header {
+			return &types.Header{
+				Number:           big.NewInt(19_426_587),
+				Time:             1710338135,
+				Difficulty:       big.NewInt(0),
+				BaseFee:          big.NewInt(1),
+				WithdrawalsHash:  &hash,
+				BlobGasUsed:      &zero,
+				ExcessBlobGas:    &zero,
+				ParentBeaconRoot: &hash,
+			}
+		}
+	)
+	tests := []struct {
+		header *types.Header
+		want   string
+	}{
+		{&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}, ""},
+		{&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), BaseFee: big.NewInt(1)}, "has base fee"},
+		{&types.Header{Number: big.NewInt(12_965_000), Difficulty: big.NewInt(1)}, "lacks base fee"},
+		{cancun(), ""},
+		{func() *types.Header { h := cancun(); h.ParentBeaconRoot = nil; return h }(), "lacks parent beacon root"},
+		{func() *types.Header { h := cancun(); h.RequestsHash = &hash; return h }(), "has requests hash"},
+		{func() *types.Header { h := cancun(); h.Difficulty = big.NewInt(1); return h }(), "after the merge"},
+	}
+	for i, tt := range tests {
+		err := verifyForkFields(params.MainnetChainConfig, tt.header)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("test %d: unexpected error %v", i, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("test %d: error %v, want %q", i, err, tt.want)
+		}
+	}
+}
+
+// TestConfigAtBlock tests that forks are evaluated at the given block number
+// rather than the number of the block, across the mainnet history.
+func TestConfigAtBlock(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100), Time: 1438269988, Difficulty: big.NewInt(1)}
+	tests := []struct {
+		at   uint64
+		want forks.Fork
+	}{
+		{0, forks.Frontier},
+		{1_149_999, forks.Frontier},
+		{1_150_000, forks.Homestead},
+		{1_920_000, forks.DAO},
+		{4_370_000, forks.Byzantium},
+		{7_280_000, forks.Petersburg},
+		{12_964_999, forks.Berlin},
+		{12_965_000, forks.London},
+		{15_050_000, forks.GrayGlacier},
+	}
+	for _, tt := range tests {
+		config := configAtBlock(params.MainnetChainConfig, header.Number, tt.at)
+		if fork := activeFork(config, header); fork != tt.want {
+			t.Errorf("block 100 at %d: active fork %v, want %v", tt.at, fork, tt.want)
+		}
+	}
+	// Petersburg defaults to Constantinople when unset, so it must stay
+	// inactive explicitly when evaluated before its activation.
+	staged := &params.ChainConfig{
+		ChainID:             big.NewInt(1),
+		HomesteadBlock:      big.NewInt(0),
+		ByzantiumBlock:      big.NewInt(0),
+		ConstantinopleBlock: big.NewInt(10),
+		PetersburgBlock:     big.NewInt(20),
+	}
+	config := configAtBlock(staged, header.Number, 15)
+	if !config.IsConstantinople(header.Number) || config.IsPetersburg(header.Number) {
+		t.Error("Constantinople block evaluated with Petersburg rules")
+	}
+	// The state change of the DAO fork applies to the block only if it is
+	// evaluated at the fork block itself.
+	if config := configAtBlock(params.MainnetChainConfig, header.Number, 1_920_000); config.DAOForkBlock.Cmp(header.Number) != 0 {
+		t.Errorf("DAO fork block %v, want the block itself", config.DAOForkBlock)
+	}
+	if config := configAtBlock(params.MainnetChainConfig, header.Number, 1_920_001); config.DAOForkBlock.Cmp(header.Number) == 0 {
+		t.Error("DAO fork state change applied after the fork block")
+	}
+	if params.MainnetChainConfig.LondonBlock.Uint64() != 12_965_000 {
+		t.Error("chain config modified")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !ziren
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// generatorBalance is the balance the senders of the transactions of a
+// generated payload are funded with in its genesis, 2^96 wei.
+var generatorBalance = new(big.Int).Lsh(big.NewInt(1), 96)
+
+// generatorGasLimit is the gas limit of the blocks of a generated payload.
+const generatorGasLimit = 30_000_000
+
+// GeneratePayload builds a payload for the first block of a fresh chain with the
+// given configuration, holding the given signed transactions in order, along
+// with the witness needed to execute it statelessly. The genesis funds the
+// senders of the transactions and holds nothing else, so the payload is fully
+// determined by its arguments. Blocks are sealed by a fake proof-of-work engine
+// before the merge and by the beacon engine after it; clique networks are not
+// supported. It returns an error if a transaction cannot be included.
+func GeneratePayload(config *params.ChainConfig, txs []*types.Transaction) (payload *Payload, err error) {
+	if config.ChainID == nil || config.ChainID.Sign() == 0 {
+		return nil, errors.New("chain config has no chain ID")
+	}
+	if config.Clique != nil {
+		return nil, errors.New("clique networks are not supported")
+	}
+	var (
+		signer = types.LatestSigner(config)
+		alloc  = make(types.GenesisAlloc)
+	)
+	for i, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %v", i, err)
+		}
+		alloc[from] = types.Account{Balance: generatorBalance}
+	}
+	genesis := &core.Genesis{Config: config, Alloc: alloc, GasLimit: generatorGasLimit}
+	engine := beacon.New(ethash.NewFaker())
+
+	// The chain maker panics on transactions that cannot be applied.
+	defer func() {
+		if value := recover(); value != nil {
+			payload, err = nil, fmt.Errorf("failed to generate block: %v", value)
+		}
+	}()
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, 1, func(i int, gen *core.BlockGen) {
+		for _, tx := range txs {
+			gen.AddTx(tx)
+		}
+	})
+
+	// Import the block into a chain to record the witness of its execution.
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), genesis, engine, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer chain.Stop()
+
+	witness, err := chain.InsertBlockWithoutSetHead(blocks[0], true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record witness: %v", err)
+	}
+	return &Payload{ChainID: config.ChainID.Uint64(), Block: blocks[0], Witness: witness}, nil
+}
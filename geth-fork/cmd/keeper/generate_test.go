@@ -0,0 +1,202 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !ziren
+
+package main
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestGeneratePayload tests that generated payloads pass validation, both
+// before and after the merge.
+func TestGeneratePayload(t *testing.T) {
+	defer func(config *params.ChainConfig) { customChainConfig = config }(customChainConfig)
+
+	key, _ := crypto.GenerateKey()
+	// A contract returning an empty runtime code, deployed by the second
+	// transaction of the block.
+	initcode := common.FromHex("0x60006000f3")
+
+	for _, config := range []*params.ChainConfig{params.TestChainConfig, params.MergedTestChainConfig} {
+		signer := types.LatestSigner(config)
+		tx := func(nonce uint64, to *common.Address, data []byte) *types.Transaction {
+			return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     nonce,
+				To:        to,
+				Gas:       100_000,
+				GasFeeCap: big.NewInt(params.InitialBaseFee),
+				Value:     big.NewInt(1),
+				Data:      data,
+			})
+		}
+		recipient := common.Address{0x0c}
+		tests := map[string][]*types.Transaction{
+			"empty":    nil,
+			"transfer": {tx(0, &recipient, nil)},
+			"create":   {tx(0, &recipient, nil), tx(1, nil, initcode)},
+		}
+		customChainConfig = config
+		for name, txs := range tests {
+			payload, err := GeneratePayload(config, txs)
+			if err != nil {
+				t.Fatalf("%s: failed to generate payload: %v", name, err)
+			}
+			input, err := rlp.EncodeToBytes(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result := process(input); !result.Valid || result.TxCount != len(txs) {
+				t.Errorf("%s: generated payload failed validation: %+v", name, result)
+			}
+		}
+	}
+	// Transactions that cannot be included are reported.
+	bad := types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.LegacyTx{Nonce: 5, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+	if _, err := GeneratePayload(params.TestChainConfig, []*types.Transaction{bad}); err == nil {
+		t.Error("payload generated with a transaction of the wrong nonce")
+	}
+}
+
+// TestCaptureReverts tests that the failed transactions of a block are reported
+// in its result.
+func TestCaptureReverts(t *testing.T) {
+	defer func(config *params.ChainConfig) { customChainConfig = config }(customChainConfig)
+	defer func(capture bool) { *captureReverts = capture }(*captureReverts)
+
+	key, _ := crypto.GenerateKey()
+	config := params.MergedTestChainConfig
+	signer := types.LatestSigner(config)
+	tx := func(nonce uint64, data []byte) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			Gas:       100_000,
+			GasFeeCap: big.NewInt(params.InitialBaseFee),
+			Data:      data,
+		})
+	}
+	// The first creation succeeds, the second one reverts without return data.
+	txs := []*types.Transaction{tx(0, common.FromHex("0x60006000f3")), tx(1, common.FromHex("0x60006000fd"))}
+	payload, err := GeneratePayload(config, txs)
+	if err != nil {
+		t.Fatalf("failed to generate payload: %v", err)
+	}
+	input, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customChainConfig, *captureReverts = config, true
+	result := process(input)
+	if !result.Valid {
+		t.Fatalf("payload failed validation: %v", result.Error)
+	}
+	want := []revertInfo{{Tx: 1, Hash: txs[1].Hash(), Reason: vm.ErrExecutionReverted.Error()}}
+	if !slices.Equal(result.Reverts, want) {
+		t.Errorf("reverts %+v, want %+v", result.Reverts, want)
+	}
+}
+
+// TestHistoricalForkRules tests that blocks are executed under the rules of the
+// forks active at their number, by validating blocks built before and after
+// London, whose fee burning changes the state root, against chain configs
+// scheduling London at different blocks, and with --at-block.
+func TestHistoricalForkRules(t *testing.T) {
+	defer func(config *params.ChainConfig) { customChainConfig = config }(customChainConfig)
+	defer func(number *uint64) { atBlock = number }(atBlock)
+
+	// Configs activating London at genesis, and two blocks in.
+	london := params.TestChainConfig
+	berlin := *params.TestChainConfig
+	berlin.LondonBlock, berlin.ArrowGlacierBlock, berlin.GrayGlacierBlock = big.NewInt(2), big.NewInt(2), big.NewInt(2)
+
+	key, _ := crypto.GenerateKey()
+	generate := func(config *params.ChainConfig) []byte {
+		tx := types.MustSignNewTx(key, types.LatestSigner(config), &types.LegacyTx{
+			To:       &common.Address{0x0c},
+			Gas:      21000,
+			GasPrice: big.NewInt(2 * params.InitialBaseFee),
+		})
+		payload, err := GeneratePayload(config, []*types.Transaction{tx})
+		if err != nil {
+			t.Fatalf("failed to generate payload: %v", err)
+		}
+		input, err := rlp.EncodeToBytes(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return input
+	}
+	preLondon, postLondon := generate(&berlin), generate(london)
+
+	two := uint64(2)
+	tests := []struct {
+		name   string
+		input  []byte
+		config *params.ChainConfig
+		at     *uint64
+		fork   string
+		want   int
+	}{
+		{"pre-London block", preLondon, &berlin, nil, "Berlin", ExitSuccess},
+		{"post-London block", postLondon, london, nil, "Gray Glacier", ExitSuccess},
+		{"pre-London block under London", preLondon, london, nil, "Gray Glacier", ExitChainConfigMismatch},
+		{"post-London block before London", postLondon, &berlin, nil, "Berlin", ExitChainConfigMismatch},
+		{"post-London block at London", postLondon, &berlin, &two, "Gray Glacier", ExitSuccess},
+		{"pre-London block at London", preLondon, &berlin, &two, "Gray Glacier", ExitChainConfigMismatch},
+	}
+	for _, tt := range tests {
+		customChainConfig, atBlock = tt.config, tt.at
+		result := process(tt.input)
+		if result.ExitCode != tt.want || result.ActiveFork != tt.fork {
+			t.Errorf("%s: exit code %d at fork %q, want %d at %q (%s)", tt.name, result.ExitCode, result.ActiveFork, tt.want, tt.fork, result.Error)
+		}
+	}
+	// Execution itself follows the config it is given: without London, the
+	// base fee is not burnt but paid to the coinbase, and the state root of
+	// the post-London block is missed.
+	for _, tt := range []struct {
+		config *params.ChainConfig
+		match  bool
+	}{
+		{london, true},
+		{&berlin, false},
+	} {
+		var payload Payload
+		if err := rlp.DecodeBytes(postLondon, &payload); err != nil {
+			t.Fatal(err)
+		}
+		root, _, err := core.ExecuteStateless(tt.config, vm.Config{}, payload.Block, payload.Witness)
+		if err != nil {
+			t.Fatalf("execution failed: %v", err)
+		}
+		if match := root == payload.Block.Root(); match != tt.match {
+			t.Errorf("London %v: state root %x matches header root %x: %v", tt.config.IsLondon(payload.Block.Number()), root, payload.Block.Root(), match)
+		}
+	}
+}
@@ -19,7 +19,6 @@
 package main
 
 import (
-	_ "embed"
 	"fmt"
 	"os"
 
@@ -58,11 +57,9 @@ func fromExtWitness(ext *ExtWitness) (*stateless.Witness, error) {
 	return w, nil
 }
 
-//go:embed 1192c3_witness.rlp
-var witnessRlp []byte
-
-//go:embed 1192c3_block.rlp
-var blockRlp []byte
+// platformInput reports whether getInput provides a payload, the embedded Hoodi
+// block in this build.
+const platformInput = true
 
 // getInput is a platform-specific function that will recover the input payload
 // and returns it as a slice. It is expected to be an RLP-encoded Payload structure
@@ -87,13 +84,7 @@ func getInput() []byte {
 		panic(err)
 	}
 
-	payload := Payload{
-		ChainID: params.HoodiChainConfig.ChainID.Uint64(),
-		Block:   &block,
-		Witness: witness,
-	}
-
-	encoded, err := rlp.EncodeToBytes(payload)
+	encoded, err := stateless.EncodePayload(params.HoodiChainConfig.ChainID.Uint64(), &block, witness)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to encode payload: %v\n", err)
 		os.Exit(20)
@@ -22,6 +22,10 @@ import (
 	zkruntime "github.com/ProjectZKM/Ziren/crates/go-runtime/zkvm_runtime"
 )
 
+// platformInput reports whether getInput provides a payload, the one supplied by
+// the zkVM host in this build.
+const platformInput = true
+
 // getInput reads the input payload from the zkVM runtime environment.
 // The zkVM host provides the RLP-encoded Payload structure containing
 // the block and witness data through the runtime's input mechanism.
@@ -0,0 +1,83 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/binary"
+	"runtime"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Fingerprint returns a compact key identifying a block together with its
+// commitments: the Keccak256 of the chain ID and block number, both as 8-byte
+// big endian integers, followed by the state, receipt and transaction roots of
+// the header. Validations of the same block always yield the same fingerprint,
+// so it can be indexed in place of the individual roots.
+func Fingerprint(chainID uint64, header *types.Header) common.Hash {
+	buf := make([]byte, 0, 16+3*common.HashLength)
+	buf = binary.BigEndian.AppendUint64(buf, chainID)
+	buf = binary.BigEndian.AppendUint64(buf, header.Number.Uint64())
+	buf = append(buf, header.Root[:]...)
+	buf = append(buf, header.ReceiptHash[:]...)
+	buf = append(buf, header.TxHash[:]...)
+	return crypto.Keccak256Hash(buf)
+}
+
+// TransactionHashes returns the hashes of the transactions of the block, in
+// block order. Each is the Keccak256 of the canonical transaction encoding, as
+// used to look the transaction up in mempools and indexes.
+func TransactionHashes(block *types.Block) []common.Hash {
+	txs := block.Transactions()
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
+// warmHashes computes the hash of the block and of all its transactions up
+// front, spreading the transactions over all CPUs. Blocks and transactions
+// memoize their hashes, so every later use during validation, such as setting
+// the transaction context of the state or deriving receipts, is served from
+// this per-payload cache instead of hashing sequentially on first use.
+func warmHashes(block *types.Block) {
+	block.Hash()
+
+	txs := block.Transactions()
+	workers := min(runtime.NumCPU(), len(txs))
+	if workers <= 1 {
+		for _, tx := range txs {
+			tx.Hash()
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for i := offset; i < len(txs); i += workers {
+				txs[i].Hash()
+			}
+		}(w)
+	}
+	wg.Wait()
+}
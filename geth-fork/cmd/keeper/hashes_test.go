@@ -0,0 +1,183 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// makeTxBlock creates the encoding of a block with the given number of
+// transactions, each carrying some calldata.
+func makeTxBlock(tb testing.TB, count int) []byte {
+	txs := make(types.Transactions, count)
+	for i := range txs {
+		txs[i] = types.NewTx(&types.DynamicFeeTx{
+			ChainID: big.NewInt(1),
+			Nonce:   uint64(i),
+			To:      &common.Address{0x01},
+			Gas:     100000,
+			Data:    make([]byte, 256),
+		})
+	}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	blob, err := rlp.EncodeToBytes(types.NewBlock(header, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return blob
+}
+
+// TestWarmHashes tests that warmed hashes match the ones computed on demand.
+func TestWarmHashes(t *testing.T) {
+	blob := makeTxBlock(t, 100)
+
+	var cold, warm types.Block
+	if err := rlp.DecodeBytes(blob, &cold); err != nil {
+		t.Fatal(err)
+	}
+	if err := rlp.DecodeBytes(blob, &warm); err != nil {
+		t.Fatal(err)
+	}
+	warmHashes(&warm)
+	if cold.Hash() != warm.Hash() {
+		t.Fatal("block hash mismatch")
+	}
+	for i, tx := range cold.Transactions() {
+		if tx.Hash() != warm.Transactions()[i].Hash() {
+			t.Fatalf("tx %d hash mismatch", i)
+		}
+	}
+}
+
+// TestTransactionHashes tests the transaction hashes of the example Hoodi block
+// against the Keccak256 of their canonical encodings, which the block commits
+// to through its transaction root.
+func TestTransactionHashes(t *testing.T) {
+	blob, err := os.ReadFile("1192c3_block.rlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block types.Block
+	if err := rlp.DecodeBytes(blob, &block); err != nil {
+		t.Fatal(err)
+	}
+	if root := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); root != block.TxHash() {
+		t.Fatalf("transaction root mismatch: %x != %x", root, block.TxHash())
+	}
+	hashes := TransactionHashes(&block)
+	if len(hashes) == 0 || len(hashes) != len(block.Transactions()) {
+		t.Fatalf("got %d hashes for %d transactions", len(hashes), len(block.Transactions()))
+	}
+	for i, tx := range block.Transactions() {
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := crypto.Keccak256Hash(enc); hashes[i] != want {
+			t.Errorf("tx %d: hash %x, want %x", i, hashes[i], want)
+		}
+	}
+}
+
+// TestFingerprint tests that fingerprints are stable for the same block and
+// differ as soon as any of the covered commitments does.
+func TestFingerprint(t *testing.T) {
+	base := &types.Header{
+		Number:      big.NewInt(1151683),
+		Root:        common.Hash{0x01},
+		ReceiptHash: common.Hash{0x02},
+		TxHash:      common.Hash{0x03},
+	}
+	want := Fingerprint(560048, base)
+	if got := Fingerprint(560048, types.CopyHeader(base)); got != want {
+		t.Fatalf("fingerprint not stable: %x != %x", got, want)
+	}
+	// Changes to fields outside the commitments must not matter.
+	other := types.CopyHeader(base)
+	other.GasUsed = 21000
+	if got := Fingerprint(560048, other); got != want {
+		t.Errorf("fingerprint depends on gas used: %x != %x", got, want)
+	}
+
+	variants := []func(h *types.Header){
+		func(h *types.Header) { h.Number = big.NewInt(1151684) },
+		func(h *types.Header) { h.Root = common.Hash{0x04} },
+		func(h *types.Header) { h.ReceiptHash = common.Hash{0x04} },
+		func(h *types.Header) { h.TxHash = common.Hash{0x04} },
+		// Swapped roots must not collide either.
+		func(h *types.Header) { h.Root, h.ReceiptHash = h.ReceiptHash, h.Root },
+	}
+	seen := map[common.Hash]int{want: -1}
+	for i, mutate := range variants {
+		header := types.CopyHeader(base)
+		mutate(header)
+		fp := Fingerprint(560048, header)
+		if j, ok := seen[fp]; ok {
+			t.Errorf("variant %d collides with %d", i, j)
+		}
+		seen[fp] = i
+	}
+	if fp := Fingerprint(1, base); fp == want {
+		t.Error("fingerprint does not depend on the chain ID")
+	}
+}
+
+// BenchmarkHashes measures the cost of the hashes needed to validate a block
+// with many transactions, both computed on first use and precomputed, where the
+// block hash is checked twice and every transaction hash is used three times,
+// as during validation.
+func BenchmarkHashes(b *testing.B) {
+	blob := makeTxBlock(b, 1000)
+
+	use := func(block *types.Block) {
+		for i := 0; i < 2; i++ {
+			block.Hash()
+		}
+		for i := 0; i < 3; i++ {
+			for _, tx := range block.Transactions() {
+				tx.Hash()
+			}
+		}
+	}
+	b.Run("on-demand", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			var block types.Block
+			rlp.DecodeBytes(blob, &block)
+			b.StartTimer()
+			use(&block)
+		}
+	})
+	b.Run("precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			var block types.Block
+			rlp.DecodeBytes(blob, &block)
+			b.StartTimer()
+			warmHashes(&block)
+			use(&block)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// startHeartbeat logs a progress line for the block at every interval until
+// the returned function is called, so that operators can tell a long but
+// healthy execution from a hung keeper. Block execution is a single blocking
+// call, so the heartbeat runs on its own goroutine.
+func startHeartbeat(interval time.Duration, number uint64) (stop func()) {
+	var (
+		start  = time.Now()
+		ticker = time.NewTicker(interval)
+		done   = make(chan struct{})
+	)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				logger.Info("Still executing block", "number", number, "elapsed", common.PrettyDuration(time.Since(start)))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// syncBuffer is a buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestHeartbeat tests that progress lines are logged while execution runs and
+// no longer once it has returned.
+func TestHeartbeat(t *testing.T) {
+	out := new(syncBuffer)
+	handler, err := newLogHandler(out, "info", logFormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(previous log.Logger) { logger = previous }(logger)
+	logger = log.NewLogger(handler)
+
+	stop := startHeartbeat(5*time.Millisecond, 42)
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	beats := strings.Count(out.String(), "Still executing block")
+	if beats < 2 || !strings.Contains(out.String(), "number=42") {
+		t.Fatalf("logged %d heartbeats: %q", beats, out.String())
+	}
+	time.Sleep(20 * time.Millisecond)
+	if after := strings.Count(out.String(), "Still executing block"); after > beats+1 {
+		t.Errorf("heartbeat kept running after execution returned: %d beats, then %d", beats, after)
+	}
+}
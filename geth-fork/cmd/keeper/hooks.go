@@ -0,0 +1,104 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// joinHooks merges the transaction, call-frame, opcode, storage and system call
+// hooks of several tracers into one set of hooks, invoking them in the order
+// given. Hooks are looked up on every call, so tracers may swap them while
+// tracing, as the JSON logger does to mute itself during system calls. It
+// returns nil if no tracers are supplied, so that the EVM runs untraced.
+func joinHooks(all ...*tracing.Hooks) *tracing.Hooks {
+	switch len(all) {
+	case 0:
+		return nil
+	case 1:
+		return all[0]
+	}
+	return &tracing.Hooks{
+		OnTxStart: func(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+			for _, h := range all {
+				if h.OnTxStart != nil {
+					h.OnTxStart(env, tx, from)
+				}
+			}
+		},
+		OnTxEnd: func(receipt *types.Receipt, err error) {
+			for _, h := range all {
+				if h.OnTxEnd != nil {
+					h.OnTxEnd(receipt, err)
+				}
+			}
+		},
+		OnEnter: func(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+			for _, h := range all {
+				if h.OnEnter != nil {
+					h.OnEnter(depth, typ, from, to, input, gas, value)
+				}
+			}
+		},
+		OnExit: func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+			for _, h := range all {
+				if h.OnExit != nil {
+					h.OnExit(depth, output, gasUsed, err, reverted)
+				}
+			}
+		},
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			for _, h := range all {
+				if h.OnOpcode != nil {
+					h.OnOpcode(pc, op, gas, cost, scope, rData, depth, err)
+				}
+			}
+		},
+		OnFault: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+			for _, h := range all {
+				if h.OnFault != nil {
+					h.OnFault(pc, op, gas, cost, scope, depth, err)
+				}
+			}
+		},
+		OnSystemCallStart: func() {
+			for _, h := range all {
+				if h.OnSystemCallStart != nil {
+					h.OnSystemCallStart()
+				}
+			}
+		},
+		OnSystemCallEnd: func() {
+			for _, h := range all {
+				if h.OnSystemCallEnd != nil {
+					h.OnSystemCallEnd()
+				}
+			}
+		},
+		OnStorageChange: func(addr common.Address, slot common.Hash, prev, new common.Hash) {
+			for _, h := range all {
+				if h.OnStorageChange != nil {
+					h.OnStorageChange(addr, slot, prev, new)
+				}
+			}
+		},
+	}
+}
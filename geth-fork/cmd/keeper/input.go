@@ -0,0 +1,335 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported encodings of the input payload.
+const (
+	formatAuto   = "auto"
+	formatRaw    = "raw"
+	formatHex    = "hex"
+	formatBase64 = "base64"
+	formatGzip   = "gzip"
+	formatZstd   = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decodeInput converts the input from the given encoding into raw RLP. In auto
+// mode the encoding is sniffed from the content, and the detected format is
+// returned alongside the decoded bytes.
+func decodeInput(input []byte, format string) ([]byte, string, error) {
+	if format == formatAuto {
+		detected, err := detectFormat(input)
+		if err != nil {
+			return nil, "", err
+		}
+		format = detected
+	}
+	var (
+		output []byte
+		err    error
+	)
+	switch format {
+	case formatRaw:
+		output = input
+	case formatHex:
+		output, err = decodeHex(input)
+	case formatBase64:
+		output, err = decodeBase64(input)
+	case formatGzip:
+		output, err = decodeGzip(input)
+	case formatZstd:
+		output, err = decodeZstd(input)
+	default:
+		err = fmt.Errorf("unknown input format %q", format)
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	if len(output) > *maxInputSize {
+		return nil, format, fmt.Errorf("decoded input exceeds maximum size (more than %d bytes)", *maxInputSize)
+	}
+	return output, format, nil
+}
+
+// detectFormat sniffs the encoding of the input. Binary formats are recognised
+// by their magic bytes or RLP list prefix, textual ones by their charset. A 0x
+// prefix always selects hex; other text that is both valid hex and valid base64
+// is resolved by checking which of the two decodes into an RLP list.
+func detectFormat(input []byte) (string, error) {
+	switch {
+	case len(input) == 0:
+		return formatRaw, nil
+	case bytes.HasPrefix(input, gzipMagic):
+		return formatGzip, nil
+	case bytes.HasPrefix(input, zstdMagic):
+		return formatZstd, nil
+	case input[0] >= 0xc0:
+		return formatRaw, nil
+	}
+	var candidates []string
+	if isHexText(input) {
+		if text := bytes.TrimSpace(input); len(trimHexPrefix(text)) != len(text) {
+			return formatHex, nil
+		}
+		candidates = append(candidates, formatHex)
+	}
+	if isBase64Text(input) {
+		candidates = append(candidates, formatBase64)
+	}
+	switch len(candidates) {
+	case 0:
+		return "", errors.New("unable to detect input format: not gzip, zstd, hex, base64 or an RLP list")
+	case 1:
+		return candidates[0], nil
+	}
+	var matches []string
+	for _, candidate := range candidates {
+		if output, _, err := decodeInput(input, candidate); err == nil && len(output) > 0 && output[0] >= 0xc0 {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("ambiguous input format, could be any of: %s", strings.Join(candidates, ", "))
+}
+
+// isHexText reports whether the input is an even-length hex string, with an
+// optional 0x prefix and surrounding whitespace.
+func isHexText(input []byte) bool {
+	text := trimHexPrefix(bytes.TrimSpace(input))
+	if len(text) == 0 || len(text)%2 != 0 {
+		return false
+	}
+	for _, c := range text {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// isBase64Text reports whether the input is padded standard or URL-safe base64,
+// ignoring surrounding whitespace.
+func isBase64Text(input []byte) bool {
+	text := bytes.TrimSpace(input)
+	if len(text) == 0 || len(text)%4 != 0 {
+		return false
+	}
+	for i, c := range text {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		case c == '+', c == '/', c == '-', c == '_':
+		case c == '=' && i >= len(text)-2:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func trimHexPrefix(text []byte) []byte {
+	if len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		return text[2:]
+	}
+	return text
+}
+
+func decodeHex(input []byte) ([]byte, error) {
+	text := trimHexPrefix(bytes.TrimSpace(input))
+	output := make([]byte, hex.DecodedLen(len(text)))
+	if _, err := hex.Decode(output, text); err != nil {
+		return nil, fmt.Errorf("invalid hex input: %v", err)
+	}
+	return output, nil
+}
+
+func decodeBase64(input []byte) ([]byte, error) {
+	text := string(bytes.TrimSpace(input))
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(text, "-_") {
+		encoding = base64.URLEncoding
+	}
+	output, err := encoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 input: %v", err)
+	}
+	return output, nil
+}
+
+// decodeGzip decompresses the input, reading at most one byte past the
+// maximum input size so that oversized payloads are still rejected without
+// being fully inflated in memory.
+func decodeGzip(input []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(input))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip input: %v", err)
+	}
+	defer r.Close()
+
+	output, err := io.ReadAll(io.LimitReader(r, int64(*maxInputSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip input: %v", err)
+	}
+	return output, nil
+}
+
+// decodeZstd decompresses the input, reading at most one byte past the
+// maximum input size like decodeGzip.
+func decodeZstd(input []byte) ([]byte, error) {
+	r, err := zstd.NewReader(bytes.NewReader(input), zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd input: %v", err)
+	}
+	defer r.Close()
+
+	output, err := io.ReadAll(io.LimitReader(r, int64(*maxInputSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd input: %v", err)
+	}
+	return output, nil
+}
+
+// errTerminalInput is returned when the payload would be read from stdin while
+// it is attached to a terminal, where the keeper would wait for input forever.
+var errTerminalInput = errors.New("no input given and stdin is a terminal")
+
+// loadInput returns the raw input payload: read from the file at the given path,
+// or from stdin if the path is "-", and obtained from the platform otherwise.
+// Builds without a platform input read stdin if no path is given.
+func loadInput(path string) ([]byte, error) {
+	if path == "" && platformInput {
+		return getInput(), nil
+	}
+	if path == "" || path == "-" {
+		if stdinTerminal() {
+			return nil, errTerminalInput
+		}
+		return readInputFile("-")
+	}
+	if stdinPiped() {
+		logger.Warn("Reading payload from file, ignoring stdin", "path", path)
+	}
+	return readInputFile(path)
+}
+
+// maxInputSizeEnv is the environment variable setting the maximum input size if
+// --max-input-size is not given.
+const maxInputSizeEnv = "KEEPER_MAX_INPUT_SIZE"
+
+// configureMaxInputSize applies the maximum input size set in the environment,
+// unless --max-input-size is given, and checks that the resulting limit is
+// positive.
+func configureMaxInputSize() error {
+	explicit := false
+	flag.Visit(func(f *flag.Flag) {
+		explicit = explicit || f.Name == "max-input-size"
+	})
+	if value, ok := os.LookupEnv(maxInputSizeEnv); ok && !explicit {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid $%s %q: %v", maxInputSizeEnv, value, err)
+		}
+		*maxInputSize = size
+	}
+	if *maxInputSize <= 0 {
+		return fmt.Errorf("maximum input size must be positive, got %d", *maxInputSize)
+	}
+	return nil
+}
+
+// maxEncodedInputSize returns the size of the largest input read before it is
+// decoded. The maximum input size applies to the decoded payload, so encoded
+// inputs may be larger: hex, the largest of the encodings, doubles the size,
+// plus a 0x prefix and some whitespace.
+func maxEncodedInputSize() int64 {
+	return 2*int64(*maxInputSize) + int64(*maxInputSize)/64 + 64
+}
+
+// readInputFile reads an input payload from the file at the given path, or from
+// stdin if the path is "-". Inputs larger than any encoding of a payload of the
+// maximum input size are rejected without reading them in full; the decoded
+// payload is checked against the maximum input size itself by decodeInput.
+func readInputFile(path string) ([]byte, error) {
+	limit := maxEncodedInputSize()
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > limit {
+			return nil, fmt.Errorf("input exceeds maximum encoded size (%d > %d)", info.Size(), limit)
+		}
+		r = f
+	}
+	input, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(input)) > limit {
+		return nil, fmt.Errorf("input exceeds maximum encoded size (more than %d bytes)", limit)
+	}
+	return input, nil
+}
+
+// stdinPiped reports whether stdin is attached to a pipe or a non-empty file,
+// rather than a terminal or nothing at all.
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return false
+	}
+	return info.Mode()&os.ModeNamedPipe != 0 || info.Size() > 0
+}
+
+// stdinTerminal reports whether stdin is attached to a terminal, or any other
+// character device except the null device, which merely reads as empty.
+func stdinTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// TestDecodeInputAuto tests that every supported encoding is detected and
+// decoded back into the original RLP.
+func TestDecodeInputAuto(t *testing.T) {
+	payload := []byte{0xc8, 0x83, 0x61, 0x62, 0x63, 0x83, 0x64, 0x65, 0x66} // ["abc", "def"]
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write(payload)
+	w.Close()
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zcompressed := zw.EncodeAll(payload, nil)
+	zw.Close()
+
+	tests := []struct {
+		name   string
+		input  []byte
+		format string
+	}{
+		{"raw", payload, formatRaw},
+		{"hex", []byte(hex.EncodeToString(payload)), formatHex},
+		{"hex with prefix", []byte("0x" + hex.EncodeToString(payload) + "\n"), formatHex},
+		{"base64", []byte(base64.StdEncoding.EncodeToString(payload)), formatBase64},
+		{"gzip", compressed.Bytes(), formatGzip},
+		{"zstd", zcompressed, formatZstd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, format, err := decodeInput(tt.input, formatAuto)
+			if err != nil {
+				t.Fatalf("decodeInput failed: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("detected format %q, want %q", format, tt.format)
+			}
+			if !bytes.Equal(output, payload) {
+				t.Errorf("decoded %x, want %x", output, payload)
+			}
+		})
+	}
+}
+
+// TestDecodeInputAutoFailures tests that undetectable, ambiguous and
+// corrupt inputs are rejected with a descriptive error.
+func TestDecodeInputAutoFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []byte
+		errorSubstr string
+	}{
+		{"truncated zstd", []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, "invalid zstd input"},
+		{"garbage", []byte("not a payload!"), "unable to detect"},
+		{"ambiguous", []byte("abcdabcd"), "ambiguous input format, could be any of: hex, base64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := decodeInput(tt.input, formatAuto)
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.errorSubstr)
+			}
+			if !strings.Contains(err.Error(), tt.errorSubstr) {
+				t.Errorf("expected error containing %q, got %q", tt.errorSubstr, err.Error())
+			}
+		})
+	}
+}
+
+// TestReadInputFile tests reading payloads from files, honouring MaxInputSize.
+func TestReadInputFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "payload.rlp")
+	if err := os.WriteFile(path, []byte{0xc1, 0x80}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	input, err := readInputFile(path)
+	if err != nil || !bytes.Equal(input, []byte{0xc1, 0x80}) {
+		t.Fatalf("readInputFile() = %x, %v", input, err)
+	}
+	// Oversized files are rejected from their size alone, a sparse file suffices
+	large := filepath.Join(dir, "large.rlp")
+	f, err := os.Create(large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(maxEncodedInputSize() + 1); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := readInputFile(large); err == nil || !strings.Contains(err.Error(), "exceeds maximum encoded size") {
+		t.Errorf("oversized input not rejected: %v", err)
+	}
+	if _, err := readInputFile(filepath.Join(dir, "missing.rlp")); err == nil {
+		t.Error("missing input file not reported")
+	}
+}
+
+// TestMaxInputSizeDecoded tests that the maximum input size applies to the
+// decoded payload rather than its encoding.
+func TestMaxInputSizeDecoded(t *testing.T) {
+	defer func(size int) { *maxInputSize = size }(*maxInputSize)
+	*maxInputSize = 100
+
+	dir := t.TempDir()
+	for _, size := range []int{80, 120} {
+		path := filepath.Join(dir, fmt.Sprintf("payload-%d.hex", size))
+		if err := os.WriteFile(path, []byte("0x"+hex.EncodeToString(bytes.Repeat([]byte{0xc0}, size))+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		raw, err := readInputFile(path)
+		if err != nil {
+			t.Fatalf("size %d: readInputFile failed: %v", size, err)
+		}
+		output, _, err := decodeInput(raw, formatHex)
+		switch {
+		case size <= *maxInputSize && (err != nil || len(output) != size):
+			t.Errorf("size %d: decodeInput() = %d bytes, %v", size, len(output), err)
+		case size > *maxInputSize && (err == nil || !strings.Contains(err.Error(), "exceeds maximum size")):
+			t.Errorf("size %d: oversized decoded input not rejected: %v", size, err)
+		}
+	}
+}
+
+// TestConfigureMaxInputSize tests that the maximum input size is taken from the
+// environment and enforced on inputs.
+func TestConfigureMaxInputSize(t *testing.T) {
+	defer func(size int) { *maxInputSize = size }(*maxInputSize)
+
+	t.Setenv(maxInputSizeEnv, "2")
+	if err := configureMaxInputSize(); err != nil || *maxInputSize != 2 {
+		t.Fatalf("configureMaxInputSize() = %v, limit %d, want 2", err, *maxInputSize)
+	}
+	if err := validateInput([]byte{0xc1, 0x80}); err != nil {
+		t.Errorf("input within the limit rejected: %v", err)
+	}
+	if err := validateInput([]byte{0xc2, 0x80, 0x80}); err == nil || !strings.Contains(err.Error(), "exceeds maximum size (3 > 2)") {
+		t.Errorf("input above the limit not rejected: %v", err)
+	}
+	for _, value := range []string{"0", "-1", "100MB"} {
+		t.Setenv(maxInputSizeEnv, value)
+		if err := configureMaxInputSize(); err == nil {
+			t.Errorf("invalid limit %q accepted", value)
+		}
+	}
+}
+
+// TestLoadInputTerminal tests that reading stdin fails immediately if it is a
+// character device such as a terminal, instead of waiting for input forever.
+func TestLoadInputTerminal(t *testing.T) {
+	defer func(stdin *os.File) { os.Stdin = stdin }(os.Stdin)
+
+	// Without a platform input, stdin is read by default as well.
+	paths := []string{"-"}
+	if !platformInput {
+		paths = append(paths, "")
+	}
+	tests := []struct {
+		device  string
+		wantErr error
+	}{
+		{"/dev/zero", errTerminalInput},
+		{os.DevNull, nil},
+	}
+	for _, tt := range tests {
+		f, err := os.Open(tt.device)
+		if err != nil {
+			t.Skipf("device unavailable: %v", err)
+		}
+		defer f.Close()
+		os.Stdin = f
+
+		for _, path := range paths {
+			input, err := loadInput(path)
+			if err != tt.wantErr {
+				t.Errorf("loadInput(%q) from %s: error %v, want %v", path, tt.device, err, tt.wantErr)
+			}
+			if err == nil && len(input) != 0 {
+				t.Errorf("loadInput(%q) from %s: read %d bytes, want none", path, tt.device, len(input))
+			}
+		}
+	}
+}
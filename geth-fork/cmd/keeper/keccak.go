@@ -0,0 +1,77 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// runKeccak runs the keccak subcommand: it hashes every file given, or stdin if
+// there are none or the file is "-", and prints one "<digest>  <file>" line per
+// input like sha256sum. Digests are Keccak256, or Keccak512 with --512. Inputs
+// that cannot be read are reported on stderr without stopping the others, and
+// turn the exit code into ExitInvalidInput.
+func runKeccak(args []string, stdin io.Reader, out io.Writer) int {
+	fs := flag.NewFlagSet("keccak", flag.ContinueOnError)
+	wide := fs.Bool("512", false, "compute Keccak512 instead of Keccak256 digests")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	code := ExitSuccess
+	for _, name := range files {
+		digest, err := keccakFile(name, stdin, *wide)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "keccak: %s: %v\n", name, err)
+			code = ExitInvalidInput
+			continue
+		}
+		fmt.Fprintf(out, "%s  %s\n", hex.EncodeToString(digest), name)
+	}
+	return code
+}
+
+// keccakFile hashes the contents of the named file, or of stdin for "-". Keccak256
+// digests are computed while reading, Keccak512 ones over the whole contents.
+func keccakFile(name string, stdin io.Reader, wide bool) ([]byte, error) {
+	r := stdin
+	if name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+	if !wide {
+		return crypto.Keccak256Reader(r)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak512(data), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestKeccak tests the digests and output format of the keccak subcommand.
+func TestKeccak(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const (
+		empty256 = "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
+		hello256 = "1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8"
+		hello512 = "52fa80662e64c128f8389c9ea6c73d4c02368004bf4463491900d11aaadca39d47de1b01361f207c512cfa79f0f92c3395c67ff7928e3f5ce3e3c852b392f976"
+	)
+	tests := []struct {
+		args []string
+		code int
+		want string
+	}{
+		{nil, ExitSuccess, empty256 + "  -\n"},
+		{[]string{path, "-"}, ExitSuccess, hello256 + "  " + path + "\n" + empty256 + "  -\n"},
+		{[]string{"--512", path}, ExitSuccess, hello512 + "  " + path + "\n"},
+		{[]string{path + ".missing", path}, ExitInvalidInput, hello256 + "  " + path + "\n"},
+	}
+	for i, tt := range tests {
+		var out bytes.Buffer
+		if code := runKeccak(tt.args, strings.NewReader(""), &out); code != tt.code {
+			t.Errorf("test %d: exit code %d, want %d", i, code, tt.code)
+		}
+		if out.String() != tt.want {
+			t.Errorf("test %d: output %q, want %q", i, out.String(), tt.want)
+		}
+	}
+}
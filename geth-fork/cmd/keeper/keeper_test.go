@@ -25,6 +25,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -248,6 +249,35 @@ func DecodePayloadSafe(input []byte, payload *Payload) error {
 	return rlp.DecodeBytes(input, payload)
 }
 
+// FuzzDecodePayloadSafe checks that decoding arbitrary input never panics, and
+// either fails with an error or yields a payload with a block and a witness.
+func FuzzDecodePayloadSafe(f *testing.F) {
+	encoded, err := rlp.EncodeToBytes(Payload{
+		ChainID: 1,
+		Block:   types.NewBlockWithHeader(&types.Header{}),
+		Witness: &stateless.Witness{},
+	})
+	if err != nil {
+		f.Fatalf("failed to encode: %v", err)
+	}
+	f.Add(encoded)
+	f.Add([]byte{})
+	f.Add([]byte{0xf9, 0x01, 0x00})
+	f.Add([]byte{0xff, 0xff, 0xff})
+	f.Add([]byte{0xc3, 0x01, 0xc0, 0xc0})
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		var payload Payload
+		if err := DecodePayloadSafe(input, &payload); err != nil {
+			return
+		}
+		if payload.Block == nil || payload.Witness == nil {
+			t.Fatalf("decoded incomplete payload from %x", input)
+		}
+		validatePayload(&payload)
+	})
+}
+
 // ValidatePayload validates payload fields
 func ValidatePayload(chainID uint64, hasBlock, hasWitness bool) error {
 	if chainID == 0 {
@@ -262,15 +292,6 @@ func ValidatePayload(chainID uint64, hasBlock, hasWitness bool) error {
 	return nil
 }
 
-// ValidationError represents a validation error
-type ValidationError struct {
-	msg string
-}
-
-func (e *ValidationError) Error() string {
-	return e.msg
-}
-
 // BenchmarkPayloadDecode benchmarks payload decoding
 func BenchmarkPayloadDecode(b *testing.B) {
 	// Create a test payload
@@ -293,3 +314,50 @@ func BenchmarkPayloadDecode(b *testing.B) {
 		_ = rlp.DecodeBytes(bytes.Clone(encoded), &decoded)
 	}
 }
+
+// BenchmarkExecuteStateless benchmarks the full validation pipeline, decoding,
+// stateless execution and root comparison, over the example Hoodi block, to
+// catch regressions in the per-block cost across go-ethereum updates.
+func BenchmarkExecuteStateless(b *testing.B) {
+	blockData, err := os.ReadFile("1192c3_block.rlp")
+	if os.IsNotExist(err) {
+		b.Skip("example block file not found")
+	}
+	witnessData, err := os.ReadFile("1192c3_witness.rlp")
+	if os.IsNotExist(err) {
+		b.Skip("example witness file not found")
+	}
+	var (
+		block      types.Block
+		extwitness ExtWitness
+	)
+	if err := rlp.DecodeBytes(blockData, &block); err != nil {
+		b.Fatalf("failed to decode block: %v", err)
+	}
+	if err := rlp.DecodeBytes(witnessData, &extwitness); err != nil {
+		b.Fatalf("failed to decode witness: %v", err)
+	}
+	witness, err := fromExtWitness(&extwitness)
+	if err != nil {
+		b.Fatalf("failed to convert witness: %v", err)
+	}
+	encoded, err := rlp.EncodeToBytes(Payload{
+		ChainID: params.HoodiChainConfig.ChainID.Uint64(),
+		Block:   &block,
+		Witness: witness,
+	})
+	if err != nil {
+		b.Fatalf("failed to encode: %v", err)
+	}
+	if result := process(encoded); !result.Valid {
+		b.Fatalf("example payload failed validation: %s", result.Error)
+	}
+
+	b.SetBytes(int64(len(encoded)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		process(encoded)
+	}
+	b.ReportMetric(float64(block.GasUsed())*float64(b.N)/b.Elapsed().Seconds(), "gas/s")
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Supported log output formats.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logger is the logger of the keeper, discarding everything until configured
+// from the command line. The keeper deliberately does not log through the root
+// logger of go-ethereum, whose packages report conditions that are expected in
+// stateless validation, such as the header roots always passed along with the
+// block, as errors.
+var logger = log.NewLogger(log.DiscardHandler())
+
+// logLevels maps the names accepted by --log-level to their levels.
+var logLevels = map[string]slog.Level{
+	"debug": log.LevelDebug,
+	"info":  log.LevelInfo,
+	"warn":  log.LevelWarn,
+	"error": log.LevelError,
+}
+
+// newLogHandler creates the handler writing log records of at least the given
+// level to w, formatted as plain text or as one JSON object per line.
+func newLogHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	lvl, ok := logLevels[level]
+	if !ok {
+		return nil, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", level)
+	}
+	switch format {
+	case logFormatText:
+		return log.NewTerminalHandlerWithLevel(w, lvl, false), nil
+	case logFormatJSON:
+		return log.JSONHandlerWithLevel(w, lvl), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want %s or %s)", format, logFormatText, logFormatJSON)
+	}
+}
+
+// logResult logs the outcome of a payload: failures at error level, preceded by
+// every finding collected with --report-all and every mismatch collected with
+// --continue-on-mismatch, anything else at info level. Transactions reverted
+// under --capture-reverts are logged first, at info level. The context is
+// prepended to the fields of the result.
+func logResult(result *Result, ctx ...any) {
+	for _, revert := range result.Reverts {
+		fields := append(slices.Clip(ctx), "number", result.Number, "tx", revert.Tx, "hash", revert.Hash, "reason", revert.Reason)
+		logger.Info("Transaction reverted", fields...)
+	}
+	switch {
+	case result.Error != "":
+		for _, finding := range result.Findings {
+			fields := append(slices.Clip(ctx), "number", result.Number, "code", finding.ExitCode, "err", finding.Error)
+			logger.Error("Structural check failed", fields...)
+		}
+		for _, mismatch := range result.Mismatches {
+			fields := append(slices.Clip(ctx), "number", result.Number, "stage", mismatch.Stage, "err", mismatch.Error)
+			logger.Error("Commitment mismatch", fields...)
+		}
+		ctx = append(ctx, "code", result.ExitCode, "err", result.errorMessage())
+		logger.Error("Block validation failed", ctx...)
+	case result.DecodeOnly:
+		ctx = append(ctx, "number", result.Number, "hash", result.Hash, "witness", result.WitnessSize)
+		logger.Info("Block decoded", ctx...)
+	case result.Deferred:
+		ctx = append(ctx, "number", result.Number, "gas", result.GasUsed)
+		logger.Info("Block deferred", ctx...)
+	default:
+		elapsed := result.timings.decode + result.timings.execution + result.timings.comparison
+		ctx = append(ctx, "number", result.Number, "hash", result.Hash, "txs", result.TxCount, "gas", result.GasUsed, "elapsed", common.PrettyDuration(elapsed))
+		if result.Signer != nil {
+			ctx = append(ctx, "signer", *result.Signer)
+		}
+		logger.Info("Block validated", ctx...)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TestLogHandler tests that log records are filtered by level and formatted as
+// requested, and that unknown settings are rejected.
+func TestLogHandler(t *testing.T) {
+	if _, err := newLogHandler(new(bytes.Buffer), "trace", logFormatText); err == nil {
+		t.Error("expected error for unknown log level")
+	}
+	if _, err := newLogHandler(new(bytes.Buffer), "info", "logfmt"); err == nil {
+		t.Error("expected error for unknown log format")
+	}
+	var out bytes.Buffer
+	handler, err := newLogHandler(&out, "warn", logFormatJSON)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	defer func(previous log.Logger) { logger = previous }(logger)
+	logger = log.NewLogger(handler)
+
+	logResult(&Result{Number: 1, Valid: true})
+	logResult((&Result{Number: 2}).fail(ExitStateRootMismatch, "state root mismatch"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("logged %d records at warn level, want 1: %q", len(lines), out.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	if record["lvl"] != "error" || record["code"] != float64(ExitStateRootMismatch) {
+		t.Errorf("unexpected record %v", record)
+	}
+}
@@ -17,15 +17,18 @@
 package main
 
 import (
+        "crypto/ecdsa"
+        "errors"
+        "flag"
         "fmt"
         "os"
         "runtime/debug"
+        "time"
 
-        "github.com/ethereum/go-ethereum/core"
         "github.com/ethereum/go-ethereum/core/stateless"
         "github.com/ethereum/go-ethereum/core/types"
-        "github.com/ethereum/go-ethereum/core/vm"
-        "github.com/ethereum/go-ethereum/rlp"
+        "github.com/ethereum/go-ethereum/crypto"
+        "github.com/ethereum/go-ethereum/log"
 )
 
 // Exit codes for different error conditions
@@ -38,22 +41,35 @@ const (
         ExitInvalidInput       = 14
         ExitDecodeFailed       = 15
         ExitValidationFailed   = 16
+        ExitWitnessInvalid     = 17
+        ExitSystemCallMismatch = 18
+        ExitBatchFailed        = 19
+        ExitTooManyTxs         = 20
+        ExitTrailingBytes      = 21
+        ExitHeaderInconsistent = 22
+        ExitForkNotActivated   = 23
+        ExitChainConfigMismatch = 24
+        ExitMemoryExceeded     = 25
+        ExitWithdrawalsMismatch = 26
+        ExitWitnessIncomplete  = 27
+        ExitTimeout            = 28
+        ExitReceiptCountMismatch = 29
+        ExitBlobGasMismatch    = 30
+        ExitResourceExhausted  = 31
+        ExitWitnessTooLarge    = 32
+        ExitInterrupted        = 33
+        ExitBlockHashMismatch  = 34
 )
 
-// MaxInputSize is the maximum allowed input size (100 MB)
+// MaxInputSize is the default maximum allowed input size (100 MB), overridden
+// by --max-input-size or $KEEPER_MAX_INPUT_SIZE
 const MaxInputSize = 100 * 1024 * 1024
 
 // Payload represents the input data for stateless execution containing
-// a block and its associated witness data for verification.
-type Payload struct {
-        ChainID uint64
-        Block   *types.Block
-        Witness *stateless.Witness
-}
-
-func init() {
-        debug.SetGCPercent(-1) // Disable garbage collection
-}
+// a block and its associated witness data for verification. It is defined in
+// core/stateless, so that producers encode payloads with the same code the
+// keeper decodes them with.
+type Payload = stateless.Payload
 
 // validateInput performs bounds checking and basic validation on the raw input
 func validateInput(input []byte) error {
@@ -63,8 +79,8 @@ func validateInput(input []byte) error {
         if len(input) == 0 {
                 return fmt.Errorf("input is empty")
         }
-        if len(input) > MaxInputSize {
-                return fmt.Errorf("input exceeds maximum size (%d > %d)", len(input), MaxInputSize)
+        if len(input) > *maxInputSize {
+                return fmt.Errorf("input exceeds maximum size (%d > %d)", len(input), *maxInputSize)
         }
         // Check for valid RLP encoding prefix
         firstByte := input[0]
@@ -77,72 +93,368 @@ func validateInput(input []byte) error {
 
 // validatePayload performs semantic validation on the decoded payload
 func validatePayload(payload *Payload) error {
+        if problems := payloadProblems(payload); len(problems) > 0 {
+                return problems[0]
+        }
+        return nil
+}
+
+// payloadProblems returns every semantic problem of the decoded payload, in
+// the order validatePayload checks them.
+func payloadProblems(payload *Payload) []error {
+        var problems []error
         if payload.ChainID == 0 {
-                return fmt.Errorf("chain ID cannot be zero")
+                problems = append(problems, &ValidationError{msg: "chain ID cannot be zero"})
         }
         if payload.Block == nil {
-                return fmt.Errorf("block is nil")
+                problems = append(problems, &ValidationError{msg: "block is nil"})
         }
         if payload.Witness == nil {
-                return fmt.Errorf("witness is nil")
+                problems = append(problems, &ValidationError{msg: "witness is nil"})
         }
         // Additional block header validation
-        header := payload.Block.Header()
-        if header == nil {
-                return fmt.Errorf("block header is nil")
+        if payload.Block != nil && blockHeader(payload.Block) == nil {
+                problems = append(problems, &ValidationError{msg: "block header is nil"})
+        }
+        return problems
+}
+
+// validateTxCount checks the number of transactions in the block against the
+// configured limit. Proving cost grows with the transaction count, and blocks
+// with implausibly many transactions are rejected before execution. A limit of
+// zero disables the check.
+func validateTxCount(block *types.Block, limit uint64) error {
+        if limit == 0 {
+                return nil
+        }
+        if count := uint64(len(block.Transactions())); count > limit {
+                return fmt.Errorf("block has too many transactions (%d > %d)", count, limit)
         }
         return nil
 }
 
+// blockHeader returns the header of the block, or nil if the block was built
+// without one. types.Block does not expose the header pointer directly and its
+// accessors dereference it unconditionally.
+func blockHeader(block *types.Block) (header *types.Header) {
+        defer func() {
+                if recover() != nil {
+                        header = nil
+                }
+        }()
+        return block.Header()
+}
+
 func main() {
-        input := getInput()
+        // Silence output before parsing, so that errors in the flags are not
+        // printed either.
+        if quietRequested(os.Args[1:]) {
+                if err := silenceOutput(); err != nil {
+                        fmt.Fprintf(os.Stderr, "Error: failed to silence output: %v\n", err)
+                        os.Exit(2)
+                }
+        }
+        flag.Parse()
+        debug.SetGCPercent(*gcPercent)
 
-        // Step 1: Validate raw input
-        if err := validateInput(input); err != nil {
-                fmt.Fprintf(os.Stderr, "input validation failed: %v\n", err)
-                os.Exit(ExitInvalidInput)
+        handler, err := newLogHandler(os.Stderr, *logLevel, *logFormat)
+        if err != nil {
+                fmt.Fprintf(os.Stderr, "Error: invalid log settings: %v\n", err)
+                flag.Usage()
+                os.Exit(2)
         }
+        logger = log.NewLogger(handler)
 
-        // Step 2: Decode RLP payload
-        var payload Payload
-        if err := rlp.DecodeBytes(input, &payload); err != nil {
-                fmt.Fprintf(os.Stderr, "failed to decode payload: %v\n", err)
-                os.Exit(ExitDecodeFailed)
+        if err := configureMaxInputSize(); err != nil {
+                fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+                flag.Usage()
+                os.Exit(2)
         }
 
-        // Step 3: Validate decoded payload
-        if err := validatePayload(&payload); err != nil {
-                fmt.Fprintf(os.Stderr, "payload validation failed: %v\n", err)
-                os.Exit(ExitValidationFailed)
+        if *keccakBackend != "" {
+                if err := crypto.SetKeccakBackend(*keccakBackend); err != nil {
+                        fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+                        flag.Usage()
+                        os.Exit(2)
+                }
+        }
+        // Hashing states pooled before counting is enabled are not counted,
+        // enable it before anything is hashed.
+        if *countKeccak {
+                crypto.SetKeccakCounting(true)
         }
 
-        // Step 4: Get chain configuration
-        chainConfig, err := getChainConfig(payload.ChainID)
-        if err != nil {
-                fmt.Fprintf(os.Stderr, "failed to get chain config: %v\n", err)
-                os.Exit(ExitUnknownChainID)
+        if flag.NArg() > 0 {
+                switch flag.Arg(0) {
+                case "repl":
+                        os.Exit(runRepl(flag.Args()[1:]))
+                case "compat-check":
+                        os.Exit(runCompatCheck(flag.Args()[1:]))
+                case "version":
+                        os.Exit(runVersion(os.Stdout))
+                case "diff-roots":
+                        os.Exit(runDiffRoots(flag.Args()[1:], os.Stdout))
+                case "serve":
+                        os.Exit(runServe(flag.Args()[1:]))
+                case "keccak":
+                        os.Exit(runKeccak(flag.Args()[1:], os.Stdin, os.Stdout))
+                case "self-test":
+                        os.Exit(runSelfTest(flag.Args()[1:], os.Stdout))
+                default:
+                        if flag.NArg() > 1 || *inputPath != "" {
+                                fmt.Fprintln(os.Stderr, "Error: expected a single input file, given either as argument or with --input")
+                                flag.Usage()
+                                os.Exit(2)
+                        }
+                        *inputPath = flag.Arg(0)
+                }
         }
-        vmConfig := vm.Config{}
 
-        // Step 5: Execute stateless validation
-        crossStateRoot, crossReceiptRoot, err := core.ExecuteStateless(chainConfig, vmConfig, payload.Block, payload.Witness)
+        if (*partialBatchOutput != "" || *sampleRate > 0 || *reverseBatch || *chainContinuity || *deferAboveGas > 0 || *prefetchDepth > 0 || *aggressiveFree || *twoPhase || *memoryPerPayload > 0 || *sinceBlock > 0 || *checkpointFile != "" || *parallel > 1 || *errorsOnly) && !*batchMode && *inputTar == "" {
+                fmt.Fprintln(os.Stderr, "Error: batch options require --batch or --input-tar")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *diffReceiptsFile != "" && (*batchMode || *inputTar != "") {
+                fmt.Fprintln(os.Stderr, "Error: --diff-receipts cannot be combined with --batch or --input-tar")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if expectedBlockHash != nil && (*batchMode || *inputTar != "") {
+                fmt.Fprintln(os.Stderr, "Error: --expect-block-hash cannot be combined with --batch or --input-tar")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *countKeccak && (*batchMode || *inputTar != "") {
+                fmt.Fprintln(os.Stderr, "Error: --count-keccak cannot be combined with --batch or --input-tar")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *traceFile != "" && (*batchMode || *inputTar != "" || *execTimeout > 0) {
+                fmt.Fprintln(os.Stderr, "Error: --trace cannot be combined with --batch, --input-tar or --timeout")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *skipStateRoot && *skipReceiptRoot {
+                fmt.Fprintln(os.Stderr, "Error: --skip-state-root and --skip-receipt-root cannot both be given, at least one root must be compared")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if (*skipStateRoot || *skipReceiptRoot) && *emitReceipt != "" {
+                fmt.Fprintln(os.Stderr, "Error: --emit-receipt cannot be combined with --skip-state-root or --skip-receipt-root")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *genesisAlloc != "" && *stateSnapshot != "" {
+                fmt.Fprintln(os.Stderr, "Error: --genesis-alloc cannot be combined with --state-snapshot")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *parallel < 1 {
+                fmt.Fprintln(os.Stderr, "Error: --parallel must be at least 1")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *parallel > 1 && (*prefetchDepth > 0 || *twoPhase || *memoryPerPayload > 0) {
+                fmt.Fprintln(os.Stderr, "Error: --parallel cannot be combined with --prefetch, --two-phase or --limit-memory-per-payload")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *twoPhase && *prefetchDepth > 0 {
+                fmt.Fprintln(os.Stderr, "Error: --two-phase cannot be combined with --prefetch")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *memoryPerPayload > 0 && *prefetchDepth > 0 {
+                fmt.Fprintln(os.Stderr, "Error: --limit-memory-per-payload cannot be combined with --prefetch")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *prefetchDepth < 0 {
+                fmt.Fprintln(os.Stderr, "Error: --prefetch must not be negative")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *sampleRate < 0 || *sampleRate > 1 {
+                fmt.Fprintln(os.Stderr, "Error: --sample must be between 0 and 1")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *deferAboveGas > 0 && *deferredOutput == "" {
+                fmt.Fprintln(os.Stderr, "Error: --defer-above-gas requires --deferred-output")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *sampleRate > 0 && *chainContinuity {
+                fmt.Fprintln(os.Stderr, "Error: --chain-continuity cannot be combined with --sample")
+                flag.Usage()
+                os.Exit(2)
+        }
+        if *chainConfigPath != "" {
+                config, err := loadChainConfig(*chainConfigPath)
+                if err != nil {
+                        fmt.Fprintf(os.Stderr, "Error: failed to load chain config: %v\n", err)
+                        os.Exit(2)
+                }
+                customChainConfig = config
+        }
+        fields, err := newFieldSelector(*outputInclude, *outputExclude)
         if err != nil {
-                fmt.Fprintf(os.Stderr, "stateless self-validation failed: %v\n", err)
-                os.Exit(ExitStatelessFailed)
+                fmt.Fprintf(os.Stderr, "Error: invalid output settings: %v\n", err)
+                flag.Usage()
+                os.Exit(2)
         }
-
-        // Step 6: Verify state root
-        if crossStateRoot != payload.Block.Root() {
-                fmt.Fprintf(os.Stderr, "stateless self-validation root mismatch (cross: %x local: %x)\n", crossStateRoot, payload.Block.Root())
-                os.Exit(ExitStateRootMismatch)
+        printResult, err := newResultPrinter(os.Stdout, *outputFormat, *outputTemplate, fields)
+        if err != nil {
+                fmt.Fprintf(os.Stderr, "Error: invalid output settings: %v\n", err)
+                flag.Usage()
+                os.Exit(2)
         }
-
-        // Step 7: Verify receipt root
-        if crossReceiptRoot != payload.Block.ReceiptHash() {
-                fmt.Fprintf(os.Stderr, "stateless self-validation receipt root mismatch (cross: %x local: %x)\n", crossReceiptRoot, payload.Block.ReceiptHash())
-                os.Exit(ExitReceiptRootMismatch)
+        if *inputTar != "" && (*batchMode || *inputPath != "") {
+                fmt.Fprintln(os.Stderr, "Error: --input-tar cannot be combined with --batch or another input")
+                flag.Usage()
+                os.Exit(2)
+        }
+        var resultFile *os.File
+        if *resultFD >= 0 {
+                if *batchMode || *inputTar != "" {
+                        fmt.Fprintln(os.Stderr, "Error: --result-fd cannot be combined with --batch or --input-tar")
+                        flag.Usage()
+                        os.Exit(2)
+                }
+                if resultFile, err = openResultFD(*resultFD); err != nil {
+                        fmt.Fprintf(os.Stderr, "Error: invalid --result-fd: %v\n", err)
+                        flag.Usage()
+                        os.Exit(2)
+                }
+        }
+        var key *ecdsa.PrivateKey
+        if *emitReceipt != "" {
+                if *batchMode || *inputTar != "" || *decodeOnly || *signingKey == "" {
+                        fmt.Fprintln(os.Stderr, "Error: --emit-receipt requires --signing-key and cannot be used with --batch, --input-tar or --decode-only")
+                        flag.Usage()
+                        os.Exit(2)
+                }
+                var err error
+                if key, err = crypto.LoadECDSA(*signingKey); err != nil {
+                        fmt.Fprintf(os.Stderr, "Error: failed to load signing key: %v\n", err)
+                        os.Exit(2)
+                }
+        }
+        var metrics *metricsWriter
+        if *metricsFile != "" {
+                metrics = newMetricsWriter(*metricsFile)
         }
+        config := &batchConfig{
+                print:      printResult,
+                errorsOnly: *errorsOnly,
+                outdir:     *partialBatchOutput,
+                append:     *outputAppend,
+                sample:     *sampleRate,
+                seed:       *sampleSeed,
+                sinceBlock: *sinceBlock,
+                checkpoint: *checkpointFile,
+                reverse:    *reverseBatch,
+                continuity: *chainContinuity,
 
-        // Success - block validated
-        os.Exit(ExitSuccess)
+                deferGas:    *deferAboveGas,
+                deferOutput: *deferredOutput,
+
+                prefetch: *prefetchDepth,
+                parallel: *parallel,
+                twoPhase: *twoPhase,
+
+                metrics: metrics,
+
+                memoryBudget: *memoryPerPayload,
+
+                collect:        *gcPercent < 0,
+                aggressiveFree: *aggressiveFree,
+        }
+        if *inputTar != "" {
+                names, payloads, err := readTarPayloads(*inputTar, *inputFormat)
+                if err != nil {
+                        logger.Error("Failed to read input archive", "path", *inputTar, "err", err)
+                        os.Exit(ExitInvalidInput)
+                }
+                // Batches stop between payloads on SIGINT or SIGTERM, so that
+                // their results and checkpoint are left consistent.
+                interrupt, stop := interruptContext()
+                config.interrupted = interrupt.Done()
+                code := runPayloads(payloads, names, config)
+                stop()
+                os.Exit(code)
+        }
+        raw, err := loadInput(*inputPath)
+        if errors.Is(err, errTerminalInput) {
+                fmt.Fprintf(os.Stderr, "Error: %v, pass a payload file or pipe one into stdin\n", err)
+                flag.Usage()
+                os.Exit(ExitInvalidInput)
+        }
+        if err != nil {
+                logger.Error("Failed to read input", "err", err)
+                os.Exit(ExitInvalidInput)
+        }
+        input, format, err := decodeInput(raw, *inputFormat)
+        if err != nil {
+                logger.Error("Input decoding failed", "err", err)
+                os.Exit(ExitInvalidInput)
+        }
+        if *inputFormat == formatAuto {
+                logger.Info("Detected input format", "format", format)
+        }
+        if *batchMode {
+                interrupt, stop := interruptContext()
+                config.interrupted = interrupt.Done()
+                code := runBatch(input, config)
+                stop()
+                os.Exit(code)
+        }
+        crypto.ResetKeccakCount()
+        result := process(input)
+        if *countKeccak {
+                counts := crypto.KeccakCount()
+                result.KeccakCalls, result.KeccakBytes = counts.Calls, counts.Bytes
+        }
+        logResult(result)
+        if *countKeccak {
+                logger.Info("Keccak256 usage", "number", result.Number, "calls", result.KeccakCalls, "bytes", result.KeccakBytes)
+        }
+        if result.DecodeOnly && printResult == nil {
+                fmt.Printf("decoded block %d on chain %d, witness %d bytes\n", result.Number, result.ChainID, result.WitnessSize)
+        }
+        if printResult != nil {
+                if err := printResult(result); err != nil {
+                        logger.Error("Failed to write result", "err", err)
+                        os.Exit(1)
+                }
+        }
+        if resultFile != nil {
+                if err := writeResult(resultFile, result); err != nil {
+                        logger.Error("Failed to write result", "fd", *resultFD, "err", err)
+                        os.Exit(1)
+                }
+        }
+        if *outputAppend != "" {
+                if err := appendResult(*outputAppend, result); err != nil {
+                        logger.Error("Failed to append result", "path", *outputAppend, "err", err)
+                        os.Exit(1)
+                }
+        }
+        if metrics != nil {
+                if err := metrics.record(result); err != nil {
+                        logger.Error("Failed to write metrics", "path", *metricsFile, "err", err)
+                }
+        }
+        if key != nil && result.Valid {
+                receipt, err := newValidationReceipt(result, uint64(time.Now().Unix()), key)
+                if err == nil {
+                        err = writeValidationReceipt(*emitReceipt, receipt)
+                }
+                if err != nil {
+                        logger.Error("Failed to emit validation receipt", "path", *emitReceipt, "err", err)
+                        os.Exit(1)
+                }
+        }
+        os.Exit(result.ExitCode)
 }
@@ -0,0 +1,142 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// memorySampleInterval is how often the memory in use is sampled while a
+// payload is processed under a memory budget.
+const memorySampleInterval = time.Millisecond
+
+// Factors projecting the memory needed to decode a payload from the size of its
+// input, and to execute it statelessly from the size of its witness. Decoding
+// copies every field of the input, while execution resolves the witness nodes
+// into tries and, with garbage collection disabled, retains every trie node
+// and state object created along the way.
+const (
+	decodeMemoryFactor    = 2
+	executionMemoryFactor = 4
+)
+
+// checkMemoryCeiling returns an error if the memory obtained by the process
+// from the operating system, plus the given projected need, exceeds the
+// ceiling in bytes. A zero ceiling disables the check.
+func checkMemoryCeiling(ceiling, need uint64) error {
+	if ceiling == 0 {
+		return nil
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	used := stats.Sys - stats.HeapReleased
+	if used+need > ceiling {
+		return fmt.Errorf("projected memory use of %d bytes (%d in use, %d needed) exceeds the limit of %d bytes", used+need, used, need, ceiling)
+	}
+	return nil
+}
+
+// memoryGuard tracks the memory used while processing a single payload against
+// a budget. While it is active, the soft memory limit of the runtime is lowered
+// to the memory in use when the payload started plus the budget, so that the
+// garbage collector runs as needed to stay within it, even if it is otherwise
+// disabled. Garbage alone thus never exceeds the budget; only a payload whose
+// live memory does not fit in it does. Processing is not interrupted, the
+// payload is failed once it completes.
+//
+// Garbage left from before is collected when the guard is started, so that it
+// can neither be mistaken for memory of the payload nor, once collected under
+// the lowered limit, hide it. The memory used by the payload is then the
+// largest rise of the memory in use over the lowest point sampled before it.
+type memoryGuard struct {
+	low    uint64 // Lowest memory in use sampled so far
+	used   uint64 // Largest rise of the memory in use over the low sampled so far
+	limit  int64  // Soft memory limit in place before the guard was started
+	quit   chan struct{}
+	done   chan struct{}
+	sample []metrics.Sample
+}
+
+// startMemoryGuard samples the memory in use and starts tracking its rise under
+// the given budget in bytes.
+func startMemoryGuard(budget uint64) *memoryGuard {
+	g := &memoryGuard{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+		sample: []metrics.Sample{
+			{Name: "/memory/classes/total:bytes"},
+			{Name: "/memory/classes/heap/released:bytes"},
+			{Name: "/memory/classes/heap/free:bytes"},
+		},
+	}
+	runtime.GC()
+	g.low = g.inUse()
+
+	// Saturate rather than wrap around on budgets too large for a limit
+	limit := int64(math.MaxInt64)
+	if budget < math.MaxInt64-g.low {
+		limit = int64(g.low + budget)
+	}
+	g.limit = debug.SetMemoryLimit(limit)
+
+	go func() {
+		defer close(g.done)
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				g.record()
+			case <-g.quit:
+				return
+			}
+		}
+	}()
+	return g
+}
+
+// inUse returns the memory currently mapped by the runtime, excluding free heap
+// memory, whether or not it was returned to the operating system. The soft
+// memory limit also counts free memory that was not returned, so keeping below
+// it keeps the memory in use below it as well.
+func (g *memoryGuard) inUse() uint64 {
+	metrics.Read(g.sample)
+	return g.sample[0].Value.Uint64() - g.sample[1].Value.Uint64() - g.sample[2].Value.Uint64()
+}
+
+// record samples the memory currently in use.
+func (g *memoryGuard) record() {
+	inUse := g.inUse()
+	g.low = min(g.low, inUse)
+	g.used = max(g.used, inUse-g.low)
+}
+
+// stop restores the previous soft memory limit and returns the memory used
+// while the guard was active.
+func (g *memoryGuard) stop() uint64 {
+	close(g.quit)
+	<-g.done
+	g.record()
+	debug.SetMemoryLimit(g.limit)
+	return g.used
+}
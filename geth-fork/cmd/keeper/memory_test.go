@@ -0,0 +1,131 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math"
+	"math/big"
+	"runtime/debug"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var ballast []byte
+
+// TestMemoryGuard tests that the memory guard measures memory kept alive while
+// it is active, and restores the previous soft memory limit.
+func TestMemoryGuard(t *testing.T) {
+	limit := debug.SetMemoryLimit(-1)
+
+	guard := startMemoryGuard(1 << 20)
+	ballast = make([]byte, 64<<20)
+	for i := range ballast {
+		ballast[i] = 1
+	}
+	if used := guard.stop(); used < 32<<20 {
+		t.Errorf("guard measured %d bytes, want at least %d", used, 32<<20)
+	}
+	ballast = nil
+
+	if restored := debug.SetMemoryLimit(-1); restored != limit {
+		t.Errorf("memory limit %d not restored to %d", restored, limit)
+	}
+}
+
+// TestMemoryGuardHugeBudget tests that a budget too large to add to the memory
+// in use lifts the soft memory limit instead of wrapping around below it.
+func TestMemoryGuardHugeBudget(t *testing.T) {
+	for _, budget := range []uint64{math.MaxUint64, math.MaxInt64, math.MaxInt64 - 1} {
+		guard := startMemoryGuard(budget)
+		limit := debug.SetMemoryLimit(-1)
+		guard.stop()
+
+		if limit != math.MaxInt64 {
+			t.Errorf("budget %d: memory limit %d, want %d", budget, limit, int64(math.MaxInt64))
+		}
+	}
+}
+
+// TestBatchMemoryBudget tests that payloads exceeding the memory budget fail
+// individually without stopping the batch.
+func TestBatchMemoryBudget(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	// The memory of an empty block may come and go between two samples of the
+	// guard, weigh the payload down with a large witness node to be hashed.
+	parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: types.EmptyRootHash}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash(), TxHash: types.EmptyTxsHash, ReceiptHash: types.EmptyReceiptsHash}
+	witness, _ := stateless.NewWitness(header, nil)
+	witness.Headers = []*types.Header{parent}
+	witness.State[string(make([]byte, 16<<20))] = struct{}{}
+	good, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: types.NewBlockWithHeader(header), Witness: witness})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		budget uint64
+		want   int
+	}{
+		{1 << 30, ExitStateRootMismatch},
+		{1, ExitMemoryExceeded},
+	}
+	for _, tt := range tests {
+		var results []*Result
+		config := &batchConfig{memoryBudget: tt.budget, print: func(result *Result) error {
+			results = append(results, result)
+			return nil
+		}}
+		if code := runBatch(makeBatch(good, good), config); code != ExitBatchFailed {
+			t.Fatalf("budget %d: exit code = %d, want %d", tt.budget, code, ExitBatchFailed)
+		}
+		if len(results) != 2 {
+			t.Fatalf("budget %d: %d results, want 2", tt.budget, len(results))
+		}
+		for i, result := range results {
+			if result.ExitCode != tt.want {
+				t.Errorf("budget %d: result %d exit code = %d, want %d", tt.budget, i, result.ExitCode, tt.want)
+			}
+		}
+	}
+}
+
+// TestMaxMemory tests that payloads projected to exceed the memory ceiling fail
+// with ExitResourceExhausted before decoding.
+func TestMaxMemory(t *testing.T) {
+	defer func(ceiling uint64) { *maxMemory = ceiling }(*maxMemory)
+
+	payload := makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)
+	tests := []struct {
+		ceiling uint64
+		stage   string
+		code    int
+	}{
+		{0, stageStateRoot, ExitStateRootMismatch},
+		{1 << 40, stageStateRoot, ExitStateRootMismatch},
+		{1, stageDecode, ExitResourceExhausted},
+	}
+	for _, tt := range tests {
+		*maxMemory = tt.ceiling
+		if result := process(payload); result.Stage != tt.stage || result.ExitCode != tt.code {
+			t.Errorf("ceiling %d: result at stage %q with exit code %d, want %q with %d", tt.ceiling, result.Stage, result.ExitCode, tt.stage, tt.code)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// stageTimings holds the time spent in the stages of the validation pipeline
+// for a single payload.
+type stageTimings struct {
+	decode     time.Duration // Input validation and RLP decoding
+	execution  time.Duration // Stateless (or stateful) execution of the block
+	comparison time.Duration // Comparison of the computed roots with the header
+}
+
+// metricsWriter maintains a file of validation metrics in the Prometheus text
+// exposition format, for collection by the node exporter's textfile collector
+// or a similar agent. The file is rewritten after every payload.
+type metricsWriter struct {
+	path string
+
+	valid, failed, deferred uint64 // Payloads seen so far, by outcome
+
+	last     *Result      // Last result that was validated in this run
+	lastTime stageTimings // Stage timings of the last validated result
+}
+
+func newMetricsWriter(path string) *metricsWriter {
+	return &metricsWriter{path: path}
+}
+
+// record accounts for the result of a payload and rewrites the metrics file.
+// Stage timings are only taken from results validated in this run, results
+// resumed from an earlier run leave the previous timings in place.
+func (m *metricsWriter) record(result *Result) error {
+	switch {
+	case result.Deferred:
+		m.deferred++
+	case result.Valid:
+		m.valid++
+	default:
+		m.failed++
+	}
+	if result.timed {
+		m.last, m.lastTime = result, result.timings
+	}
+	return writeFileAtomic(m.path, m.encode())
+}
+
+// encode renders the metrics in the Prometheus text exposition format.
+func (m *metricsWriter) encode() []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintln(&buf, "# HELP keeper_payloads_total Payloads processed, by outcome.")
+	fmt.Fprintln(&buf, "# TYPE keeper_payloads_total counter")
+	fmt.Fprintf(&buf, "keeper_payloads_total{outcome=\"valid\"} %d\n", m.valid)
+	fmt.Fprintf(&buf, "keeper_payloads_total{outcome=\"failed\"} %d\n", m.failed)
+	fmt.Fprintf(&buf, "keeper_payloads_total{outcome=\"deferred\"} %d\n", m.deferred)
+
+	if m.last == nil {
+		return buf.Bytes()
+	}
+	fmt.Fprintln(&buf, "# HELP keeper_stage_duration_seconds Time spent in each validation stage for the last payload.")
+	fmt.Fprintln(&buf, "# TYPE keeper_stage_duration_seconds gauge")
+	fmt.Fprintf(&buf, "keeper_stage_duration_seconds{stage=\"decode\"} %g\n", m.lastTime.decode.Seconds())
+	fmt.Fprintf(&buf, "keeper_stage_duration_seconds{stage=\"execution\"} %g\n", m.lastTime.execution.Seconds())
+	fmt.Fprintf(&buf, "keeper_stage_duration_seconds{stage=\"comparison\"} %g\n", m.lastTime.comparison.Seconds())
+
+	fmt.Fprintln(&buf, "# HELP keeper_block_number Number of the last validated block.")
+	fmt.Fprintln(&buf, "# TYPE keeper_block_number gauge")
+	fmt.Fprintf(&buf, "keeper_block_number %d\n", m.last.Number)
+	fmt.Fprintln(&buf, "# HELP keeper_block_gas_used Gas used by the last validated block.")
+	fmt.Fprintln(&buf, "# TYPE keeper_block_gas_used gauge")
+	fmt.Fprintf(&buf, "keeper_block_gas_used %d\n", m.last.GasUsed)
+	return buf.Bytes()
+}
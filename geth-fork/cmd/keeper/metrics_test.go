@@ -0,0 +1,67 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestMetricsWriter tests that the metrics file counts outcomes and reports the
+// stage timings of validated payloads only.
+func TestMetricsWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keeper.prom")
+	metrics := newMetricsWriter(path)
+
+	read := func() string {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(blob)
+	}
+	// Resumed results are counted but carry no timings.
+	if err := metrics.record(&Result{Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if out := read(); !strings.Contains(out, `keeper_payloads_total{outcome="valid"} 1`) || strings.Contains(out, "keeper_stage_duration_seconds") {
+		t.Errorf("unexpected metrics for resumed result:\n%s", out)
+	}
+	if err := metrics.record(process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))); err != nil {
+		t.Fatal(err)
+	}
+	out := read()
+	for _, want := range []string{
+		`keeper_payloads_total{outcome="failed"} 1`,
+		`keeper_stage_duration_seconds{stage="decode"}`,
+		`keeper_stage_duration_seconds{stage="execution"}`,
+		`keeper_stage_duration_seconds{stage="comparison"}`,
+		"keeper_block_number 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics lack %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `{stage="execution"} 0`+"\n") {
+		t.Errorf("execution time not measured:\n%s", out)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gofrs/flock"
+)
+
+// writeResult writes the result to the given writer as a single line of JSON.
+func writeResult(w io.Writer, result *Result) error {
+	return json.NewEncoder(w).Encode(result)
+}
+
+// fieldSelector selects the fields of the JSON result to output.
+type fieldSelector struct {
+	fields []string // JSON names of the selected fields, in result order
+}
+
+// resultFields returns the JSON names of the fields of a result, in order.
+func resultFields() []string {
+	var names []string
+	typ := reflect.TypeOf(Result{})
+	for i := 0; i < typ.NumField(); i++ {
+		if tag := typ.Field(i).Tag.Get("json"); tag != "" && tag != "-" {
+			names = append(names, strings.Split(tag, ",")[0])
+		}
+	}
+	return names
+}
+
+// newFieldSelector creates a selector from comma-separated lists of fields to
+// include and exclude. Without includes, all fields are included. Nil is
+// returned if no selection is made. Unknown fields are rejected.
+func newFieldSelector(include, exclude string) (*fieldSelector, error) {
+	if include == "" && exclude == "" {
+		return nil, nil
+	}
+	known := resultFields()
+	parse := func(list string) (map[string]bool, error) {
+		set := make(map[string]bool)
+		if list == "" {
+			return set, nil
+		}
+		for _, field := range strings.Split(list, ",") {
+			field = strings.TrimSpace(field)
+			if !slices.Contains(known, field) {
+				return nil, fmt.Errorf("unknown result field %q (known fields: %s)", field, strings.Join(known, ", "))
+			}
+			set[field] = true
+		}
+		return set, nil
+	}
+	included, err := parse(include)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := parse(exclude)
+	if err != nil {
+		return nil, err
+	}
+	selector := new(fieldSelector)
+	for _, field := range known {
+		if (include == "" || included[field]) && !excluded[field] {
+			selector.fields = append(selector.fields, field)
+		}
+	}
+	return selector, nil
+}
+
+// encode encodes the selected fields of the result as a JSON object, in the
+// order of the full result. Fields omitted from the full result when empty are
+// omitted here as well.
+func (s *fieldSelector) encode(result *Result) ([]byte, error) {
+	blob, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(blob, &all); err != nil {
+		return nil, err
+	}
+	out := []byte{'{'}
+	for _, field := range s.fields {
+		value, ok := all[field]
+		if !ok {
+			continue
+		}
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		out = strconv.AppendQuote(out, field)
+		out = append(out, ':')
+		out = append(out, value...)
+	}
+	return append(out, '}'), nil
+}
+
+// newResultPrinter returns the function writing each result to w in the given
+// output format, or as rendered by the given text/template if one is set. Nil
+// is returned for the text format, where results are only reported through
+// stderr and the exit code. Templates are test-rendered against an empty result,
+// so that mistakes fail at startup rather than in the middle of a batch. A field
+// selector, if set, trims the JSON format to the selected fields.
+func newResultPrinter(w io.Writer, format string, tmpl string, fields *fieldSelector) (func(*Result) error, error) {
+	if fields != nil && (tmpl != "" || format != "json") {
+		return nil, errors.New("field selection requires the json output format")
+	}
+	if tmpl != "" {
+		t, err := template.New("output").Parse(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.Execute(io.Discard, new(Result)); err != nil {
+			return nil, err
+		}
+		return func(result *Result) error {
+			if err := t.Execute(w, result); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "\n")
+			return err
+		}, nil
+	}
+	switch format {
+	case "text":
+		return nil, nil
+	case "json":
+		if fields != nil {
+			return func(result *Result) error {
+				blob, err := fields.encode(result)
+				if err != nil {
+					return err
+				}
+				_, err = w.Write(append(blob, '\n'))
+				return err
+			}, nil
+		}
+		return func(result *Result) error { return writeResult(w, result) }, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// openResultFD returns the file of the open file descriptor the result is to be
+// written to, as handed over by a supervisor. Descriptors that are not open are
+// rejected up front, rather than losing the result once validation completes.
+func openResultFD(fd int) (*os.File, error) {
+	if fd < 0 {
+		return nil, fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd %d", fd))
+	if _, err := f.Stat(); err != nil {
+		return nil, fmt.Errorf("file descriptor %d is not open: %v", fd, err)
+	}
+	return f, nil
+}
+
+// quietRequested reports whether the command line arguments set --quiet. The
+// arguments are scanned as flag.Parse does, so that output can be silenced
+// before parsing, when it would print errors in the flags.
+func quietRequested(args []string) bool {
+	quiet := false
+	for len(args) > 0 {
+		arg := args[0]
+		args = args[1:]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg[1:], "-"), "=")
+		f := flag.CommandLine.Lookup(name)
+		if f == nil {
+			break
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			if name == "quiet" {
+				quiet = true
+				if hasValue {
+					quiet, _ = strconv.ParseBool(value)
+				}
+			}
+			continue
+		}
+		// Skip the value of non-boolean flags given as a separate argument.
+		if !hasValue && len(args) > 0 {
+			args = args[1:]
+		}
+	}
+	return quiet
+}
+
+// appendResult appends the result as a single line of JSON to the file at the
+// given path, creating it if needed. The file is locked for the duration of the
+// write, so that concurrent keeper instances sharing one results log never
+// interleave their lines.
+func appendResult(path string, result *Result) error {
+	blob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	lock := flock.New(path)
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(blob, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestAppendResult tests that concurrently appended results each end up on a
+// line of their own.
+func TestAppendResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(number uint64) {
+			defer wg.Done()
+			if err := appendResult(path, &Result{Number: number, Valid: true}); err != nil {
+				t.Errorf("append %d failed: %v", number, err)
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	seen := make(map[uint64]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("invalid result line %q: %v", scanner.Text(), err)
+		}
+		seen[result.Number] = true
+	}
+	if len(seen) != 16 {
+		t.Errorf("found %d distinct results, want 16", len(seen))
+	}
+}
+
+// TestResultPrinter tests rendering results through output templates, and that
+// broken templates are rejected up front.
+func TestResultPrinter(t *testing.T) {
+	var out bytes.Buffer
+	print, err := newResultPrinter(&out, "text", "{{.Number}},{{.StateRoot}},{{.Valid}}", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := print(&Result{Number: 5, StateRoot: common.Hash{0x01}, Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := "5," + common.Hash{0x01}.Hex() + ",true\n"
+	if out.String() != want {
+		t.Errorf("rendered %q, want %q", out.String(), want)
+	}
+	for _, tmpl := range []string{"{{.Number", "{{.NoSuchField}}"} {
+		if _, err := newResultPrinter(&out, "text", tmpl, nil); err == nil {
+			t.Errorf("template %q accepted", tmpl)
+		}
+	}
+	if print, err := newResultPrinter(&out, "text", "", nil); print != nil || err != nil {
+		t.Errorf("text output: printer %v, error %v", print != nil, err)
+	}
+	if _, err := newResultPrinter(&out, "xml", "", nil); err == nil {
+		t.Error("unknown output format accepted")
+	}
+}
+
+// TestFieldSelector tests trimming the JSON result to selected fields.
+func TestFieldSelector(t *testing.T) {
+	result := &Result{ChainID: 1, Number: 5, Valid: true, Error: "", ExitCode: 0}
+
+	tests := []struct {
+		include, exclude string
+		want             string
+	}{
+		{"number,valid,chainId", "", `{"chainId":1,"number":5,"valid":true}`},
+		{"number,error", "", `{"number":5}`},
+		{"number,valid", "valid", `{"number":5}`},
+	}
+	for i, tt := range tests {
+		var out bytes.Buffer
+		fields, err := newFieldSelector(tt.include, tt.exclude)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		print, err := newResultPrinter(&out, "json", "", fields)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if err := print(result); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(out.String()); got != tt.want {
+			t.Errorf("test %d: output %s, want %s", i, got, tt.want)
+		}
+	}
+	fields, err := newFieldSelector("", "hash,parentHash,stateRoot,receiptRoot,expectedStateRoot,expectedReceiptRoot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, _ := fields.encode(result)
+	if want := `{"chainId":1,"number":5,"gasUsed":0,"gasLimit":0,"txCount":0,"valid":true,"exitCode":0}`; string(blob) != want {
+		t.Errorf("excluded output %s, want %s", blob, want)
+	}
+	if _, err := newFieldSelector("number,bogus", ""); err == nil {
+		t.Error("unknown field accepted")
+	}
+	if fields, _ := newFieldSelector("number", ""); fields != nil {
+		if _, err := newResultPrinter(io.Discard, "text", "", fields); err == nil {
+			t.Error("field selection accepted for text output")
+		}
+	}
+}
+
+// TestQuietRequested tests that --quiet is found in the arguments as flag.Parse
+// would find it.
+func TestQuietRequested(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"--quiet"}, true},
+		{[]string{"-quiet=true", "payload.rlp"}, true},
+		{[]string{"--quiet=false"}, false},
+		{[]string{"--output", "json", "--quiet"}, true},
+		{[]string{"--input", "--quiet"}, false},
+		{[]string{"payload.rlp", "--quiet"}, false},
+		{[]string{"--", "--quiet"}, false},
+		{[]string{"--no-such-flag", "--quiet"}, false},
+	}
+	for i, tt := range tests {
+		if have := quietRequested(tt.args); have != tt.want {
+			t.Errorf("test %d: quietRequested(%q) = %v, want %v", i, tt.args, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// TestOpenResultFD tests that results can be written to an inherited file
+// descriptor, and that descriptors which are not open are rejected.
+func TestOpenResultFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// Hand over a duplicate of the write end, as the keeper owns and closes
+	// the descriptor it is given.
+	fd, err := syscall.Dup(int(w.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	f, err := openResultFD(fd)
+	if err != nil {
+		t.Fatalf("failed to open result descriptor: %v", err)
+	}
+	if err := writeResult(f, &Result{Number: 7, ExitCode: ExitStateRootMismatch}); err != nil {
+		t.Fatalf("failed to write result: %v", err)
+	}
+	f.Close()
+
+	line, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result Result
+	if err := json.Unmarshal(line, &result); err != nil || result.Number != 7 || result.ExitCode != ExitStateRootMismatch {
+		t.Errorf("unexpected result line %q (%v)", line, err)
+	}
+	if bytes.Count(line, []byte{'\n'}) != 1 {
+		t.Errorf("result is not a single line: %q", line)
+	}
+	// Descriptors closed by now may be reused by other tests, probe one far
+	// beyond those in use instead.
+	for _, fd := range []int{-1, 1 << 20} {
+		if _, err := openResultFD(fd); err == nil {
+			t.Errorf("descriptor %d accepted", fd)
+		}
+	}
+}
+
+// TestSilenceOutput tests that the stdout and stderr descriptors themselves are
+// pointed at the null device, not only the files of the os package.
+func TestSilenceOutput(t *testing.T) {
+	var saved []int
+	for _, fd := range []int{unix.Stdout, unix.Stderr} {
+		dup, err := unix.Dup(fd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, dup)
+	}
+	defer func() {
+		for i, fd := range []int{unix.Stdout, unix.Stderr} {
+			unix.Dup2(saved[i], fd)
+			unix.Close(saved[i])
+		}
+	}()
+	if err := silenceOutput(); err != nil {
+		t.Fatalf("failed to silence output: %v", err)
+	}
+	null, err := os.Stat(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		info, err := f.Stat()
+		if err != nil || !os.SameFile(info, null) {
+			t.Errorf("descriptor %d does not point at %s: %v", f.Fd(), os.DevNull, err)
+		}
+	}
+}
@@ -0,0 +1,597 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Stages of the validation pipeline, reported along with failures.
+const (
+	stageDecode      = "decode"
+	stageValidate    = "validate"
+	stageStateless   = "stateless"
+	stageStateRoot   = "stateRoot"
+	stageReceiptRoot = "receiptRoot"
+)
+
+// Result is the outcome of validating a single payload. Block fields are only
+// populated once the payload has been decoded, and computed roots only once
+// stateless execution has completed.
+type Result struct {
+	Member              string           `json:"member,omitempty"`
+	ChainID             uint64           `json:"chainId"`
+	Number              uint64           `json:"number"`
+	Hash                common.Hash      `json:"hash"`
+	ParentHash          common.Hash      `json:"parentHash"`
+	Fingerprint         common.Hash      `json:"fingerprint,omitzero"`
+	ActiveFork          string           `json:"activeFork,omitempty"`
+	ExtraData           hexutil.Bytes    `json:"extraData,omitempty"`
+	Signer              *common.Address  `json:"signer,omitempty"`
+	GasUsed             uint64           `json:"gasUsed"`
+	GasLimit            uint64           `json:"gasLimit"`
+	TxCount             int              `json:"txCount"`
+	AccessedAddresses   []common.Address `json:"accessedAddresses,omitempty"`
+	Reverts             []revertInfo     `json:"reverts,omitempty"`
+	StateRoot           common.Hash      `json:"stateRoot"`
+	ReceiptRoot         common.Hash      `json:"receiptRoot"`
+	ExpectedStateRoot   common.Hash      `json:"expectedStateRoot"`
+	ExpectedReceiptRoot common.Hash      `json:"expectedReceiptRoot"`
+	TransactionHashes   []common.Hash    `json:"transactionHashes,omitempty"`
+	WitnessSize         uint64           `json:"witnessSize,omitempty"`
+	KeccakCalls         uint64           `json:"keccakCalls,omitempty"`
+	KeccakBytes         uint64           `json:"keccakBytes,omitempty"`
+	Valid               bool             `json:"valid"`
+	DecodeOnly          bool             `json:"decodeOnly,omitempty"`
+	Deferred            bool             `json:"deferred,omitempty"`
+	Stage               string           `json:"stage,omitempty"`
+	Error               string           `json:"error,omitempty"`
+	Mismatches          []Mismatch       `json:"mismatches,omitempty"`
+	Findings            []Finding        `json:"findings,omitempty"`
+	Skipped             []string         `json:"skipped,omitempty"`
+	ExitCode            int              `json:"exitCode"`
+	Build               *BuildInfo       `json:"build,omitempty"`
+
+	err     error        // Error the result failed with, for errors.As by library callers
+	timings stageTimings // Time spent in the pipeline stages
+	timed   bool         // Whether the payload went through the pipeline in this run
+}
+
+// fail marks the result as failed at the current stage with the given exit
+// code and error message. Errors passed with %w are retained for inspection.
+func (r *Result) fail(code int, format string, args ...any) *Result {
+	r.err = fmt.Errorf(format, args...)
+	r.ExitCode = code
+	r.Error = r.err.Error()
+	return r
+}
+
+// Mismatch is a commitment check that failed while validating a payload with
+// --continue-on-mismatch, along with the stage it failed at.
+type Mismatch struct {
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// mismatch fails the result for a commitment check that did not hold, and
+// reports whether validation has to stop there. With --continue-on-mismatch,
+// every mismatch is recorded and validation goes on with the remaining checks;
+// the first mismatch determines the exit code and error of the result.
+func (r *Result) mismatch(code int, format string, args ...any) bool {
+	if r.err == nil {
+		r.fail(code, format, args...)
+	}
+	if !*continueOnMismatch {
+		return true
+	}
+	r.Mismatches = append(r.Mismatches, Mismatch{Stage: r.Stage, Error: fmt.Errorf(format, args...).Error()})
+	return false
+}
+
+// Finding is a structural check that failed while validating a payload with
+// --report-all, along with the exit code it would have failed with.
+type Finding struct {
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error"`
+}
+
+// finding fails the result for a structural check that did not hold, and
+// reports whether validation has to stop there. With --report-all, every
+// failed check is recorded and the checks independent of it still run; the
+// first failure determines the exit code and error of the result.
+func (r *Result) finding(code int, format string, args ...any) bool {
+	if r.err == nil {
+		r.fail(code, format, args...)
+	}
+	if !*reportAll {
+		return true
+	}
+	r.Findings = append(r.Findings, Finding{ExitCode: code, Error: fmt.Errorf(format, args...).Error()})
+	return false
+}
+
+// errorMessage returns the error of a failed result for logging, identifying
+// the block it failed on, so log lines can be correlated with the chain. Blocks
+// are only known once the payload has been decoded and validated; earlier
+// failures are marked as such.
+func (r *Result) errorMessage() string {
+	if r.Hash == (common.Hash{}) {
+		return fmt.Sprintf("%s (block unknown, payload not decoded)", r.Error)
+	}
+	return fmt.Sprintf("block %d (%s): %s", r.Number, r.Hash.Hex(), r.Error)
+}
+
+// ValidationResult holds the block identity and the roots computed while
+// validating a payload with Validate.
+type ValidationResult struct {
+	Number      uint64
+	Hash        common.Hash
+	StateRoot   common.Hash
+	ReceiptRoot common.Hash
+}
+
+// StageError is returned by Validate when a payload fails validation. It
+// carries the pipeline stage the failure occurred in and the exit code the
+// keeper reports for it, and wraps the error of the stage: a DecodeError,
+// ValidationError, UnknownChainIDError, StateRootMismatchError or
+// ReceiptRootMismatchError where applicable.
+type StageError struct {
+	Stage    string
+	ExitCode int
+	Err      error
+}
+
+func (e *StageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Validate runs the validation pipeline over a decoded payload without exiting
+// the process, allowing payloads to be validated in-process. The result holds
+// whatever was computed before a failure, the error is a *StageError.
+func Validate(payload *Payload) (*ValidationResult, error) {
+	if payload == nil {
+		return nil, &StageError{Stage: stageValidate, ExitCode: ExitValidationFailed, Err: fmt.Errorf("payload validation failed: %w", &ValidationError{msg: "payload is nil"})}
+	}
+	result := processPayload(payload, new(Result))
+	validation := &ValidationResult{
+		Number:      result.Number,
+		Hash:        result.Hash,
+		StateRoot:   result.StateRoot,
+		ReceiptRoot: result.ReceiptRoot,
+	}
+	if !result.Valid {
+		return validation, &StageError{Stage: result.Stage, ExitCode: result.ExitCode, Err: result.err}
+	}
+	return validation, nil
+}
+
+// process runs the validation pipeline over a single RLP-encoded payload. It
+// never exits the process; the outcome, including the exit code the keeper
+// should terminate with, is reported in the returned result.
+func process(input []byte) *Result {
+	payload, result := decodePayload(input)
+	if payload == nil {
+		return result
+	}
+	return processPayload(payload, result)
+}
+
+// decodePayload runs the decoding stage of the validation pipeline. If decoding
+// fails, the returned payload is nil and the result reports the failure.
+func decodePayload(input []byte) (*Payload, *Result) {
+	result := &Result{Stage: stageDecode, Build: currentBuild(), timed: true}
+	start := time.Now()
+	defer func() { result.timings.decode = time.Since(start) }()
+	logger.Debug("Decoding payload", "size", len(input))
+
+	// Step 1: Validate raw input
+	if err := validateInput(input); err != nil {
+		return nil, result.fail(ExitInvalidInput, "input validation failed: %v", err)
+	}
+
+	if err := checkMemoryCeiling(*maxMemory, uint64(len(input))*decodeMemoryFactor); err != nil {
+		return nil, result.fail(ExitResourceExhausted, "resource exhausted: %v", err)
+	}
+
+	// Step 2: Decode RLP payload. Bytes following the payload usually point to
+	// a framing or concatenation bug in the producer, report them separately.
+	if _, _, rest, err := rlp.Split(input); err == nil && len(rest) > 0 {
+		offset := len(input) - len(rest)
+		return nil, result.fail(ExitTrailingBytes, "failed to decode payload: %w", &DecodeError{Offset: offset, Reason: fmt.Sprintf("%d trailing bytes after the payload (%d bytes)", len(rest), offset)})
+	}
+	if *verifyCodes {
+		if err := verifyWitnessCodes(input); err != nil {
+			return nil, result.fail(ExitWitnessInvalid, "witness validation failed: %v", err)
+		}
+	}
+	payload := new(Payload)
+	if err := decodeRLP(input, payload); err != nil {
+		return nil, result.fail(ExitDecodeFailed, "failed to decode payload: %w", describeDecodeError(input, err))
+	}
+	logger.Debug("Decoded payload", "number", payload.Block.NumberU64(), "hash", payload.Block.Hash(), "elapsed", common.PrettyDuration(time.Since(start)))
+	return payload, result
+}
+
+// processPayload runs the remaining stages of the validation pipeline over a
+// decoded payload, completing the result of its decoding.
+func processPayload(payload *Payload, result *Result) *Result {
+	// Step 3: Validate decoded payload
+	result.Stage = stageValidate
+	for _, err := range payloadProblems(payload) {
+		if result.finding(ExitValidationFailed, "payload validation failed: %w", err) {
+			return result
+		}
+	}
+	if len(result.Findings) > 0 {
+		return result
+	}
+	result.ChainID = payload.ChainID
+	result.Number = payload.Block.NumberU64()
+	result.Hash = payload.Block.Hash()
+	result.ParentHash = payload.Block.ParentHash()
+	result.GasUsed = payload.Block.GasUsed()
+	result.GasLimit = payload.Block.GasLimit()
+	result.TxCount = len(payload.Block.Transactions())
+	result.WitnessSize = witnessSize(payload.Witness)
+	result.ExpectedStateRoot = payload.Block.Root()
+	result.ExpectedReceiptRoot = payload.Block.ReceiptHash()
+	result.Fingerprint = Fingerprint(payload.ChainID, payload.Block.Header())
+	if *txHashes {
+		result.TransactionHashes = TransactionHashes(payload.Block)
+	}
+	// The roots only attest to a block; an expected hash pins down which one.
+	if expectedBlockHash != nil && result.Hash != *expectedBlockHash {
+		if result.finding(ExitBlockHashMismatch, "payload validation failed: block hash %x does not match the expected hash %x", result.Hash, *expectedBlockHash) {
+			return result
+		}
+	}
+
+	if *decodeOnly {
+		// Findings of the checks above still fail the payload.
+		if len(result.Findings) > 0 {
+			return result
+		}
+		result.Valid = true
+		result.DecodeOnly = true
+		result.Stage = ""
+		return result
+	}
+	if err := validateWitnessSize(payload.Witness, *maxWitnessSize); err != nil {
+		if result.finding(ExitWitnessTooLarge, "witness validation failed: %v", err) {
+			return result
+		}
+	}
+	if err := validateWitnessNodes(payload.Witness, *maxWitnessNodes); err != nil {
+		if result.finding(ExitWitnessTooLarge, "witness validation failed: %v", err) {
+			return result
+		}
+	}
+	if err := validateTxCount(payload.Block, *maxTxs); err != nil {
+		if result.finding(ExitTooManyTxs, "payload validation failed: %v", err) {
+			return result
+		}
+	}
+	if *precomputeHashes {
+		warmHashes(payload.Block)
+	}
+
+	// Step 4: Get chain configuration
+	// The remaining checks depend on the chain configuration.
+	chainConfig, err := getChainConfig(payload.ChainID)
+	if err != nil {
+		result.finding(ExitUnknownChainID, "failed to get chain config: %w", err)
+		return result
+	}
+	if atBlock != nil {
+		chainConfig = configAtBlock(chainConfig, payload.Block.Number(), *atBlock)
+	}
+	fork := activeFork(chainConfig, payload.Block.Header())
+	result.ActiveFork = fork.String()
+
+	// On Clique networks, report who sealed the block. A missing or broken seal
+	// is not a validation failure, as stateless execution does not check seals.
+	if chainConfig.Clique != nil {
+		result.ExtraData = payload.Block.Extra()
+		if signer, err := cliqueSigner(payload.Block.Header()); err != nil {
+			logger.Warn("Failed to recover Clique signer", "number", result.Number, "err", err)
+		} else {
+			result.Signer = &signer
+		}
+	}
+
+	if err := verifyForkFields(chainConfig, payload.Block.Header()); err != nil {
+		if result.finding(ExitChainConfigMismatch, "payload validation failed: block does not match the chain config: %v", err) {
+			return result
+		}
+	}
+	if requiredFork != nil && fork < *requiredFork {
+		if result.finding(ExitForkNotActivated, "payload validation failed: block %d is at fork %v, %v is required", result.Number, fork, *requiredFork) {
+			return result
+		}
+	}
+
+	// A genesis allocation replaces the pre-state of the witness, which then
+	// only needs to serve the ancestor headers.
+	verifyWitness := verifyWitnessComplete
+	if *genesisAlloc != "" {
+		verifyWitness = verifyWitnessHeaders
+	}
+	if err := verifyWitness(payload.Block, payload.Witness); err != nil {
+		if result.finding(ExitWitnessIncomplete, "witness validation failed: %v", err) {
+			return result
+		}
+	}
+	if err := verifyBlobGas(chainConfig, payload.Block, witnessParent(payload.Witness)); err != nil {
+		if result.finding(ExitBlobGasMismatch, "header validation failed: %v", err) {
+			return result
+		}
+	}
+	// Empty blocks commit to the empty transaction and receipt tries, catch
+	// producers getting them wrong without executing the block. A receipt root
+	// mismatch is left to execution when continuing past mismatches, which is
+	// then going to report it along with the others.
+	if err := verifyEmptyTxRoot(payload.Block); err != nil {
+		if result.finding(ExitHeaderInconsistent, "header validation failed: %v", err) {
+			return result
+		}
+	}
+	if !*skipReceiptRoot && !*continueOnMismatch {
+		if err := verifyEmptyReceiptRoot(payload.Block); err != nil {
+			if result.finding(ExitReceiptRootMismatch, "header validation failed: %w", err) {
+				return result
+			}
+		}
+	}
+	if *strict {
+		if err := verifyWithdrawalsRoot(payload.Block); err != nil {
+			if result.mismatch(ExitWithdrawalsMismatch, "payload validation failed: %v", err) {
+				return result
+			}
+		}
+	}
+	if *checkDifficulty {
+		if err := verifyDifficulty(chainConfig, payload.Block.Header(), witnessParent(payload.Witness)); err != nil {
+			if result.finding(ExitHeaderInconsistent, "header validation failed: %v", err) {
+				return result
+			}
+		}
+	}
+	if len(result.Findings) > 0 {
+		return result
+	}
+
+	var (
+		tracers  []*tracing.Hooks
+		accesses *accessTracer
+		reverts  *revertTracer
+		syscalls *systemCallTracer
+		recorder *receiptRecorder
+		receipts = new(receiptCounter)
+	)
+	tracers = append(tracers, receipts.hooks())
+	if *checkAccessLists || *accessedAddresses {
+		accesses = newAccessTracer()
+		tracers = append(tracers, accesses.hooks())
+	}
+	if *captureReverts {
+		reverts = newRevertTracer()
+		tracers = append(tracers, reverts.hooks())
+	}
+	if *checkSystemCalls {
+		syscalls = newSystemCallTracer()
+		tracers = append(tracers, syscalls.hooks())
+	}
+	if *diffReceiptsFile != "" {
+		recorder = new(receiptRecorder)
+		tracers = append(tracers, recorder.hooks())
+	}
+	if *traceFile != "" {
+		file, err := os.Create(*traceFile)
+		if err != nil {
+			return result.fail(ExitInvalidInput, "failed to create trace file: %v", err)
+		}
+		trace := bufio.NewWriter(file)
+		defer func() {
+			if err := errors.Join(trace.Flush(), file.Close()); err != nil {
+				logger.Error("Failed to write execution trace", "path", *traceFile, "err", err)
+			}
+		}()
+		tracers = append(tracers, newTraceHooks(trace))
+	}
+	vmConfig := vm.Config{Tracer: joinHooks(tracers...)}
+
+	// Step 5: Execute stateless validation, or stateful validation against a
+	// snapshot, cross-checked with the witness
+	result.Stage = stageStateless
+	if err := checkMemoryCeiling(*maxMemory, result.WitnessSize*executionMemoryFactor); err != nil {
+		return result.fail(ExitResourceExhausted, "resource exhausted: %v", err)
+	}
+	start := time.Now()
+	defer func() {
+		if result.timings.execution == 0 {
+			result.timings.execution = time.Since(start) // Execution failed
+		}
+	}()
+	if *heartbeat > 0 {
+		defer startHeartbeat(*heartbeat, result.Number)()
+	}
+	var deadline time.Time
+	if *execTimeout > 0 {
+		deadline = start.Add(*execTimeout)
+	}
+	timedOut := func(err error) bool { return errors.Is(err, errExecutionTimeout) }
+	panicked := func(err error) bool { return errors.As(err, new(*executionPanic)) }
+
+	var crossStateRoot, crossReceiptRoot common.Hash
+	if *stateSnapshot != "" {
+		snapshot, err := loadSnapshot(*stateSnapshot, payload.Witness.Root())
+		if err != nil {
+			return result.fail(ExitInvalidInput, "failed to load state snapshot: %v", err)
+		}
+		crossStateRoot, crossReceiptRoot, err = runWithDeadline(deadline, func() (common.Hash, common.Hash, error) {
+			return core.ExecuteStateful(chainConfig, vmConfig, payload.Block, payload.Witness, snapshot)
+		})
+		if timedOut(err) {
+			return result.fail(ExitTimeout, "stateful execution aborted after %v: %w", *execTimeout, err)
+		}
+		if panicked(err) {
+			return result.fail(ExitValidationFailed, "stateful execution failed: %w", err)
+		}
+		if reverts != nil {
+			result.Reverts = reverts.reverts
+		}
+		if err != nil {
+			return result.fail(ExitStatelessFailed, "stateful self-validation failed: %v", err)
+		}
+		witnessStateRoot, witnessReceiptRoot, err := runWithDeadline(deadline, func() (common.Hash, common.Hash, error) {
+			return core.ExecuteStateless(chainConfig, vm.Config{}, payload.Block, payload.Witness)
+		})
+		if timedOut(err) {
+			return result.fail(ExitTimeout, "stateless execution aborted after %v: %w", *execTimeout, err)
+		}
+		if panicked(err) {
+			return result.fail(ExitValidationFailed, "stateless execution failed: %w", err)
+		}
+		if err != nil {
+			return result.fail(ExitWitnessInvalid, "witness disagrees with state snapshot: stateless execution failed: %v", err)
+		}
+		if witnessStateRoot != crossStateRoot || witnessReceiptRoot != crossReceiptRoot {
+			return result.fail(ExitWitnessInvalid, "witness disagrees with state snapshot (stateless state root %x receipt root %x, stateful state root %x receipt root %x)", witnessStateRoot, witnessReceiptRoot, crossStateRoot, crossReceiptRoot)
+		}
+	} else if *genesisAlloc != "" {
+		statedb, err := loadGenesisAlloc(*genesisAlloc, payload.Witness.Root())
+		if err != nil {
+			return result.fail(ExitInvalidInput, "failed to load genesis allocation: %v", err)
+		}
+		crossStateRoot, crossReceiptRoot, err = runWithDeadline(deadline, func() (common.Hash, common.Hash, error) {
+			return core.ExecuteStateful(chainConfig, vmConfig, payload.Block, payload.Witness, statedb)
+		})
+		if timedOut(err) {
+			return result.fail(ExitTimeout, "stateful execution aborted after %v: %w", *execTimeout, err)
+		}
+		if panicked(err) {
+			return result.fail(ExitValidationFailed, "stateful execution failed: %w", err)
+		}
+		if reverts != nil {
+			result.Reverts = reverts.reverts
+		}
+		if err != nil {
+			return result.fail(ExitStatelessFailed, "stateful self-validation failed: %v", err)
+		}
+	} else {
+		crossStateRoot, crossReceiptRoot, err = runWithDeadline(deadline, func() (common.Hash, common.Hash, error) {
+			return core.ExecuteStateless(chainConfig, vmConfig, payload.Block, payload.Witness)
+		})
+		if timedOut(err) {
+			return result.fail(ExitTimeout, "stateless execution aborted after %v: %w", *execTimeout, err)
+		}
+		if panicked(err) {
+			return result.fail(ExitValidationFailed, "stateless execution failed: %w", err)
+		}
+		if reverts != nil {
+			result.Reverts = reverts.reverts
+		}
+		if err != nil {
+			return result.fail(ExitStatelessFailed, "stateless self-validation failed: %v", err)
+		}
+	}
+	result.timings.execution = time.Since(start)
+	result.StateRoot = crossStateRoot
+	result.ReceiptRoot = crossReceiptRoot
+	logger.Debug("Executed block", "number", result.Number, "txs", result.TxCount, "gas", result.GasUsed, "elapsed", common.PrettyDuration(result.timings.execution))
+
+	if *accessedAddresses {
+		result.AccessedAddresses = accesses.addresses()
+	}
+	if *checkAccessLists {
+		report, err := compareAccessLists(chainConfig, payload.Block, payload.Witness, accesses)
+		if err != nil {
+			logger.Error("Access list comparison failed", "number", result.Number, "err", err)
+		} else {
+			report.print(os.Stderr)
+		}
+	}
+	if syscalls != nil {
+		if err := verifySystemCalls(chainConfig, payload.Block.Header(), payload.Witness, syscalls); err != nil {
+			return result.fail(ExitSystemCallMismatch, "system call verification failed: %v", err)
+		}
+	}
+
+	if err := verifyReceiptCount(payload.Block, receipts.count); err != nil {
+		if result.mismatch(ExitReceiptCountMismatch, "receipt validation failed: %v", err) {
+			return result
+		}
+	}
+
+	if recorder != nil && crossReceiptRoot != payload.Block.ReceiptHash() {
+		reportReceiptDiffs(*diffReceiptsFile, payload.Block, recorder.receipts)
+	}
+
+	// Step 6: Verify state root
+	result.Stage = stageStateRoot
+	start = time.Now()
+	defer func() { result.timings.comparison = time.Since(start) }()
+	if *skipStateRoot {
+		logger.Debug("Skipping state root comparison", "number", result.Number, "computed", crossStateRoot)
+		result.Skipped = append(result.Skipped, stageStateRoot)
+	} else {
+		logger.Debug("Comparing state root", "number", result.Number, "expected", payload.Block.Root(), "computed", crossStateRoot)
+		if crossStateRoot != payload.Block.Root() {
+			if result.mismatch(ExitStateRootMismatch, "%w", &StateRootMismatchError{Expected: payload.Block.Root(), Actual: crossStateRoot}) {
+				return result
+			}
+		}
+	}
+
+	// Step 7: Verify receipt root
+	result.Stage = stageReceiptRoot
+	if *skipReceiptRoot {
+		logger.Debug("Skipping receipt root comparison", "number", result.Number, "computed", crossReceiptRoot)
+		result.Skipped = append(result.Skipped, stageReceiptRoot)
+	} else {
+		logger.Debug("Comparing receipt root", "number", result.Number, "expected", payload.Block.ReceiptHash(), "computed", crossReceiptRoot)
+		if crossReceiptRoot != payload.Block.ReceiptHash() {
+			if result.mismatch(ExitReceiptRootMismatch, "%w", &ReceiptRootMismatchError{Expected: payload.Block.ReceiptHash(), Actual: crossReceiptRoot}) {
+				return result
+			}
+		}
+	}
+	if len(result.Mismatches) > 0 {
+		result.Stage = result.Mismatches[0].Stage
+		return result
+	}
+
+	// Success - block validated
+	result.Valid = true
+	result.Stage = ""
+	return result
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/forks"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// makeEmptyPayload creates a payload for an empty Frontier block on top of an
+// empty state, declaring the given roots.
+func makeEmptyPayload(t *testing.T, stateRoot, receiptRoot common.Hash) []byte {
+	parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: types.EmptyRootHash}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash(), Root: stateRoot, TxHash: types.EmptyTxsHash, ReceiptHash: receiptRoot}
+	witness, _ := stateless.NewWitness(header, nil)
+	witness.Headers = []*types.Header{parent}
+	input, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: types.NewBlockWithHeader(header), Witness: witness})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+// TestResultStages tests that failures are attributed to the pipeline stage
+// they occurred in, and that the JSON result carries both sets of roots.
+func TestResultStages(t *testing.T) {
+	result := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	if result.Stage != stageStateRoot || result.ExitCode != ExitStateRootMismatch {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if result.WitnessSize == 0 || result.TxCount != 0 {
+		t.Errorf("unexpected block weight: witness %d bytes, %d transactions", result.WitnessSize, result.TxCount)
+	}
+	computed := result.StateRoot
+
+	tests := []struct {
+		input []byte
+		stage string
+		code  int
+	}{
+		{[]byte{0x01}, stageDecode, ExitInvalidInput},
+		{[]byte{0xc1, 0xc0}, stageDecode, ExitDecodeFailed},
+		{makeEmptyPayload(t, computed, common.Hash{}), stageValidate, ExitReceiptRootMismatch},
+		{makeEmptyPayload(t, computed, types.EmptyReceiptsHash), "", ExitSuccess},
+	}
+	for i, tt := range tests {
+		result := process(tt.input)
+		if result.Stage != tt.stage || result.ExitCode != tt.code {
+			t.Errorf("test %d: stage %q exit code %d, want %q %d", i, result.Stage, result.ExitCode, tt.stage, tt.code)
+		}
+	}
+	var out bytes.Buffer
+	if err := writeResult(&out, result); err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(out.Bytes(), &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"chainId", "number", "stateRoot", "receiptRoot", "expectedStateRoot", "expectedReceiptRoot", "valid", "stage", "error"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("JSON result lacks %q", key)
+		}
+	}
+}
+
+// TestValidate tests that in-process validation reports the computed roots and
+// the failing stage as a typed error.
+func TestValidate(t *testing.T) {
+	decode := func(input []byte) *Payload {
+		payload := new(Payload)
+		if err := rlp.DecodeBytes(input, payload); err != nil {
+			t.Fatal(err)
+		}
+		return payload
+	}
+	result, err := Validate(decode(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)))
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != stageStateRoot || stageErr.ExitCode != ExitStateRootMismatch {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result == nil || result.StateRoot == (common.Hash{}) {
+		t.Fatalf("computed roots not reported: %+v", result)
+	}
+	var rootErr *StateRootMismatchError
+	if !errors.As(err, &rootErr) || rootErr.Actual != result.StateRoot || rootErr.Expected != (common.Hash{}) {
+		t.Errorf("state root mismatch not reported as typed error: %v", err)
+	}
+	var receiptErr *ReceiptRootMismatchError
+	_, err = Validate(decode(makeEmptyPayload(t, result.StateRoot, common.Hash{0x01})))
+	if !errors.As(err, &receiptErr) || receiptErr.Actual != types.EmptyReceiptsHash || receiptErr.Expected != (common.Hash{0x01}) {
+		t.Errorf("receipt root mismatch not reported as typed error: %v", err)
+	}
+	unknown := decode(makeEmptyPayload(t, result.StateRoot, types.EmptyReceiptsHash))
+	unknown.ChainID = 99999
+	var chainErr *UnknownChainIDError
+	if _, err := Validate(unknown); !errors.As(err, &chainErr) || chainErr.ChainID != 99999 {
+		t.Errorf("unknown chain ID not reported as typed error: %v", err)
+	}
+	valid, err := Validate(decode(makeEmptyPayload(t, result.StateRoot, types.EmptyReceiptsHash)))
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if valid.ReceiptRoot != types.EmptyReceiptsHash || valid.Number != 1 {
+		t.Errorf("unexpected result %+v", valid)
+	}
+	var validationErr *ValidationError
+	if _, err := Validate(nil); !errors.As(err, &stageErr) || stageErr.ExitCode != ExitValidationFailed || !errors.As(err, &validationErr) {
+		t.Errorf("nil payload: unexpected error %v", err)
+	}
+}
+
+// TestDecodeOnly tests that decode-only mode stops after structural validation.
+func TestDecodeOnly(t *testing.T) {
+	defer func(decode bool) { *decodeOnly = decode }(*decodeOnly)
+	*decodeOnly = true
+
+	result := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	if !result.Valid || !result.DecodeOnly || result.ExitCode != ExitSuccess || result.StateRoot != (common.Hash{}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if result.Number != 1 || result.ChainID != 1 || result.WitnessSize == 0 {
+		t.Errorf("block details not reported: %+v", result)
+	}
+	if result := process([]byte{0xc1, 0xc0}); result.Valid || result.ExitCode != ExitDecodeFailed {
+		t.Errorf("undecodable payload accepted: %+v", result)
+	}
+}
+
+// TestContinueOnMismatch tests that all commitment mismatches are reported when
+// continuing past them, with the first one determining the outcome.
+func TestContinueOnMismatch(t *testing.T) {
+	defer func(cont bool) { *continueOnMismatch = cont }(*continueOnMismatch)
+	*continueOnMismatch = true
+
+	result := process(makeEmptyPayload(t, common.Hash{}, common.Hash{}))
+	if result.Valid || result.Stage != stageStateRoot || result.ExitCode != ExitStateRootMismatch {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.Mismatches) != 2 || result.Mismatches[0].Stage != stageStateRoot || result.Mismatches[1].Stage != stageReceiptRoot {
+		t.Fatalf("unexpected mismatches %+v", result.Mismatches)
+	}
+	var rootErr *StateRootMismatchError
+	if !errors.As(result.err, &rootErr) || result.Error != result.Mismatches[0].Error {
+		t.Errorf("first mismatch not reported as the error: %v", result.err)
+	}
+	valid := process(makeEmptyPayload(t, result.StateRoot, types.EmptyReceiptsHash))
+	if !valid.Valid || valid.ExitCode != ExitSuccess || len(valid.Mismatches) != 0 {
+		t.Errorf("unexpected result %+v", valid)
+	}
+}
+
+// TestReportAll tests that all failed structural checks are reported when
+// running every one of them, with the first one determining the outcome, and
+// that the block is not executed.
+func TestReportAll(t *testing.T) {
+	defer func(all bool) { *reportAll = all }(*reportAll)
+	defer func(size uint64) { *maxWitnessSize = size }(*maxWitnessSize)
+	defer func(fork *forks.Fork) { requiredFork = fork }(requiredFork)
+	london := forks.London
+	*maxWitnessSize, requiredFork = 1, &london
+
+	result := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	if result.ExitCode != ExitWitnessTooLarge || len(result.Findings) != 0 {
+		t.Fatalf("fail-fast result %+v, want the witness size failure only", result)
+	}
+	*reportAll = true
+	result = process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	if result.Valid || result.Stage != stageValidate || result.ExitCode != ExitWitnessTooLarge || result.StateRoot != (common.Hash{}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.Findings) != 2 || result.Findings[0].ExitCode != ExitWitnessTooLarge || result.Findings[1].ExitCode != ExitForkNotActivated {
+		t.Fatalf("unexpected findings %+v", result.Findings)
+	}
+	if result.Error != result.Findings[0].Error {
+		t.Errorf("first finding not reported as the error: %v", result.Error)
+	}
+	// Problems of the payload structure are all reported as well.
+	result = processPayload(&Payload{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})}, new(Result))
+	if len(result.Findings) != 2 || result.ExitCode != ExitValidationFailed {
+		t.Errorf("unexpected findings %+v", result.Findings)
+	}
+}
+
+// TestErrorMessage tests that logged errors identify the failed block, or note
+// that it is unknown when the payload could not be decoded.
+func TestErrorMessage(t *testing.T) {
+	result := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	want := fmt.Sprintf("block %d (%s): ", result.Number, result.Hash.Hex())
+	if msg := result.errorMessage(); !strings.HasPrefix(msg, want) || !strings.HasSuffix(msg, result.Error) {
+		t.Errorf("error message %q does not identify the block", msg)
+	}
+	result = process([]byte{0x05})
+	if msg := result.errorMessage(); !strings.Contains(msg, "block unknown") {
+		t.Errorf("error message %q does not note the unknown block", msg)
+	}
+}
+
+// TestSkipRoots tests that skipped root comparisons neither fail validation nor
+// go unreported.
+func TestSkipRoots(t *testing.T) {
+	defer func(state, receipt bool) { *skipStateRoot, *skipReceiptRoot = state, receipt }(*skipStateRoot, *skipReceiptRoot)
+
+	// Learn the state root computed for the empty block, to declare either
+	// root correctly.
+	stateRoot := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)).StateRoot
+	badState := makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)
+	badReceipt := makeEmptyPayload(t, stateRoot, common.Hash{})
+
+	tests := []struct {
+		input          []byte
+		state, receipt bool
+		code           int
+		skipped        []string
+	}{
+		{badState, false, false, ExitStateRootMismatch, nil},
+		{badState, true, false, ExitSuccess, []string{stageStateRoot}},
+		{badState, false, true, ExitStateRootMismatch, nil},
+		{badReceipt, false, false, ExitReceiptRootMismatch, nil},
+		{badReceipt, false, true, ExitSuccess, []string{stageReceiptRoot}},
+	}
+	for i, tt := range tests {
+		*skipStateRoot, *skipReceiptRoot = tt.state, tt.receipt
+		result := process(tt.input)
+		if result.ExitCode != tt.code || result.Valid != (tt.code == ExitSuccess) {
+			t.Errorf("test %d: exit code = %d, valid = %v, want %d", i, result.ExitCode, result.Valid, tt.code)
+		}
+		if !slices.Equal(result.Skipped, tt.skipped) {
+			t.Errorf("test %d: skipped %v, want %v", i, result.Skipped, tt.skipped)
+		}
+	}
+}
+
+// TestExpectBlockHash tests that blocks are checked against the expected hash.
+func TestExpectBlockHash(t *testing.T) {
+	defer func(hash *common.Hash) { expectedBlockHash = hash }(expectedBlockHash)
+
+	stateRoot := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)).StateRoot
+	input := makeEmptyPayload(t, stateRoot, types.EmptyReceiptsHash)
+	hash := process(input).Hash
+
+	expectedBlockHash = &hash
+	if result := process(input); !result.Valid {
+		t.Fatalf("block with the expected hash failed: %v", result.Error)
+	}
+	expectedBlockHash = &common.Hash{0x01}
+	result := process(input)
+	if result.Valid || result.ExitCode != ExitBlockHashMismatch || result.Stage != stageValidate {
+		t.Errorf("block with another hash: exit code = %d, stage = %q, want %d in %q", result.ExitCode, result.Stage, ExitBlockHashMismatch, stageValidate)
+	}
+	// A mismatch recorded as a finding must not let a decode-only run pass.
+	defer func(all, only bool) { *reportAll, *decodeOnly = all, only }(*reportAll, *decodeOnly)
+	*reportAll, *decodeOnly = true, true
+	result = process(input)
+	if result.Valid || result.DecodeOnly || result.ExitCode != ExitBlockHashMismatch || len(result.Findings) != 1 {
+		t.Errorf("decode-only block with another hash: valid = %v, exit code = %d, findings %v, want invalid with %d", result.Valid, result.ExitCode, result.Findings, ExitBlockHashMismatch)
+	}
+}
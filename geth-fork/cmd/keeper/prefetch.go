@@ -0,0 +1,85 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+// decodedPayload is the outcome of decoding a payload ahead of its validation.
+// If the payload was fully processed ahead, the payload is nil and the result
+// final.
+type decodedPayload struct {
+	payload *Payload
+	result  *Result
+}
+
+// prefetcher processes the payloads of a batch in the background, in the order
+// they will be validated, so that the work on the next payloads overlaps with
+// the validation of the current one. Depending on the work function, payloads
+// are only decoded ahead, or also executed by parallel workers. At most depth
+// payloads are processed ahead of the one being validated. Their outcomes are
+// held in memory until they are consumed, so the depth also bounds the memory
+// taken by prefetching.
+type prefetcher struct {
+	slots map[int]chan decodedPayload // Decoded payloads by batch position
+	slot  chan struct{}               // Semaphore limiting the prefetch depth
+	quit  chan struct{}
+}
+
+// newPrefetcher starts running the given work on the payloads at the given
+// batch positions, on up to depth of them at a time.
+func newPrefetcher(order []int, depth int, work func(i int) decodedPayload) *prefetcher {
+	p := &prefetcher{
+		slots: make(map[int]chan decodedPayload, len(order)),
+		slot:  make(chan struct{}, depth),
+		quit:  make(chan struct{}),
+	}
+	for _, i := range order {
+		p.slots[i] = make(chan decodedPayload, 1)
+	}
+	go func() {
+		for _, i := range order {
+			select {
+			case p.slot <- struct{}{}:
+			case <-p.quit:
+				return
+			}
+			go func(i int) {
+				p.slots[i] <- work(i)
+			}(i)
+		}
+	}()
+	return p
+}
+
+// get waits for the payload at the given batch position to be processed.
+func (p *prefetcher) get(i int) (*Payload, *Result) {
+	decoded := <-p.slots[i]
+	<-p.slot
+	return decoded.payload, decoded.result
+}
+
+// discard releases the payload at the given batch position without waiting for
+// it, if it turns out not to need validation.
+func (p *prefetcher) discard(i int) {
+	go func() {
+		<-p.slots[i]
+		<-p.slot
+	}()
+}
+
+// close stops prefetching further payloads.
+func (p *prefetcher) close() {
+	close(p.quit)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestBatchPrefetch tests that prefetching yields the same results as decoding
+// each payload right before its validation, also when some are resumed.
+func TestBatchPrefetch(t *testing.T) {
+	batch := makeBatch(
+		makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash),
+		[]byte{0xc1, 0xc0},
+		makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash),
+		[]byte{0x05},
+		makeEmptyPayload(t, common.Hash{0x01}, common.Hash{}),
+	)
+	results := func(config *batchConfig) []*Result {
+		config.outdir = t.TempDir()
+		runBatch(batch, config)
+
+		var results []*Result
+		for i := 0; i < 5; i++ {
+			result, err := readResultFile(config.outdir, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+	want := results(&batchConfig{})
+	for _, depth := range []int{1, 2, 8} {
+		if have := results(&batchConfig{prefetch: depth}); !reflect.DeepEqual(have, want) {
+			t.Errorf("depth %d: results differ from unprefetched run", depth)
+		}
+		if have := results(&batchConfig{prefetch: depth, reverse: true}); !reflect.DeepEqual(have, want) {
+			t.Errorf("depth %d: reverse results differ from unprefetched run", depth)
+		}
+	}
+	// Resumed payloads are skipped without waiting for their decoding.
+	config := &batchConfig{outdir: t.TempDir(), prefetch: 1}
+	for i := 0; i < 4; i++ {
+		writeResultFile(config.outdir, i, &Result{Valid: true})
+	}
+	runBatch(batch, config)
+	if result, err := readResultFile(config.outdir, 4); err != nil || !reflect.DeepEqual(result, want[4]) {
+		t.Errorf("last payload result %+v, %v, want %+v", result, err, want[4])
+	}
+}
+
+// TestBatchParallel tests that validating payloads on parallel workers yields
+// the same results as validating them one by one, also when some are resumed.
+func TestBatchParallel(t *testing.T) {
+	batch := makeBatch(
+		makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash),
+		[]byte{0xc1, 0xc0},
+		makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash),
+		[]byte{0x05},
+		makeEmptyPayload(t, common.Hash{0x01}, common.Hash{}),
+	)
+	results := func(config *batchConfig) []*Result {
+		config.outdir = t.TempDir()
+		runBatch(batch, config)
+
+		var results []*Result
+		for i := 0; i < 5; i++ {
+			result, err := readResultFile(config.outdir, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+	want := results(&batchConfig{})
+	for _, workers := range []int{2, 4, 8} {
+		if have := results(&batchConfig{parallel: workers}); !reflect.DeepEqual(have, want) {
+			t.Errorf("%d workers: results differ from sequential run", workers)
+		}
+		if have := results(&batchConfig{parallel: workers, reverse: true}); !reflect.DeepEqual(have, want) {
+			t.Errorf("%d workers: reverse results differ from sequential run", workers)
+		}
+	}
+	// Resumed payloads are neither revalidated nor overwritten.
+	config := &batchConfig{outdir: t.TempDir(), parallel: 4}
+	for i := 0; i < 4; i++ {
+		writeResultFile(config.outdir, i, &Result{Valid: true})
+	}
+	if code := runBatch(batch, config); code != ExitBatchFailed {
+		t.Fatalf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	for i := 0; i < 4; i++ {
+		if result, err := readResultFile(config.outdir, i); err != nil || !result.Valid {
+			t.Errorf("resumed result %d = %+v, %v, want stored result", i, result, err)
+		}
+	}
+	if result, err := readResultFile(config.outdir, 4); err != nil || !reflect.DeepEqual(result, want[4]) {
+		t.Errorf("last payload result %+v, %v, want %+v", result, err, want[4])
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// VerifyTrieProof verifies a Merkle proof of the given key against a trie root
+// and returns the value committed under the key. The proof is the list of RLP
+// encoded trie nodes along the path of the key, from the root down, as found in
+// an execution witness or returned by eth_getProof; each node is looked up by
+// its Keccak256 hash. A proof of absence yields a nil value and no error.
+//
+// The key is the path in the trie. The state and storage tries are secure
+// tries, keyed by the Keccak256 of the account address or storage slot, so the
+// key of an account proof against a state root is crypto.Keccak256(address).
+func VerifyTrieProof(root common.Hash, key []byte, proof [][]byte) (value []byte, err error) {
+	db := memorydb.New()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return trie.VerifyProof(root, key, db)
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// proveKey returns the proof of the key in the trie as a list of nodes.
+func proveKey(t *testing.T, tr *trie.Trie, key []byte) [][]byte {
+	var list trienode.ProofList
+	if err := tr.Prove(key, &list); err != nil {
+		t.Fatalf("failed to prove key %x: %v", key, err)
+	}
+	proof := make([][]byte, len(list))
+	for i, node := range list {
+		proof[i] = node
+	}
+	return proof
+}
+
+// TestVerifyTrieProof tests that proofs generated from a trie verify against its
+// root, and that proofs against another root or with missing nodes fail.
+func TestVerifyTrieProof(t *testing.T) {
+	tr := trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.HashDefaults))
+	for i := byte(0); i < 100; i++ {
+		key := crypto.Keccak256([]byte{i})
+		tr.MustUpdate(key, bytes.Repeat([]byte{i + 1}, 40))
+	}
+	root := tr.Hash()
+
+	key := crypto.Keccak256([]byte{42})
+	proof := proveKey(t, tr, key)
+	value, err := VerifyTrieProof(root, key, proof)
+	if err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+	if !bytes.Equal(value, bytes.Repeat([]byte{43}, 40)) {
+		t.Errorf("proven value = %x, want %x", value, bytes.Repeat([]byte{43}, 40))
+	}
+	if _, err := VerifyTrieProof(common.Hash{0x01}, key, proof); err == nil {
+		t.Error("proof verified against a different root")
+	}
+	if _, err := VerifyTrieProof(root, key, proof[:len(proof)-1]); err == nil {
+		t.Error("proof verified with its last node missing")
+	}
+
+	// Absent keys are proven by the nodes along their path.
+	absent := crypto.Keccak256([]byte("absent"))
+	if value, err := VerifyTrieProof(root, absent, proveKey(t, tr, absent)); err != nil || value != nil {
+		t.Errorf("absence proof = %x, %v, want nil value", value, err)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// receiptRecorder records the receipts generated while executing a block, in
+// transaction order.
+type receiptRecorder struct {
+	receipts types.Receipts
+}
+
+// hooks returns the tracing hooks feeding the recorder.
+func (r *receiptRecorder) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxEnd: func(receipt *types.Receipt, err error) {
+			if receipt != nil {
+				r.receipts = append(r.receipts, receipt)
+			}
+		},
+	}
+}
+
+// loadReceipts loads the expected receipts of a block from a JSON array of
+// receipts, as returned by eth_getBlockReceipts.
+func loadReceipts(path string) (types.Receipts, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var receipts types.Receipts
+	if err := json.Unmarshal(blob, &receipts); err != nil {
+		return nil, fmt.Errorf("invalid receipts file: %v", err)
+	}
+	return receipts, nil
+}
+
+// receiptDiff is a field of a receipt committed to by the receipt root whose
+// computed value differs from the expected one.
+type receiptDiff struct {
+	Index    int         // Position of the receipt in the block
+	TxHash   common.Hash // Hash of the transaction of the receipt
+	Field    string      // Name of the field, as in the JSON receipt
+	Computed string
+	Expected string
+}
+
+// diffReceipts compares the computed receipts of the block with the expected
+// ones, field by field, over the fields committed to by the receipt root: the
+// type, status or post-state root, cumulative gas used, logs bloom and logs,
+// and additionally the gas used by each transaction, which is derived from the
+// cumulative gas. Receipts missing on either side are reported as such.
+func diffReceipts(block *types.Block, computed, expected types.Receipts) []receiptDiff {
+	var (
+		txs   = block.Transactions()
+		diffs []receiptDiff
+	)
+	for i := 0; i < max(len(computed), len(expected)); i++ {
+		diff := func(field string, computed, expected any) {
+			diff := receiptDiff{Index: i, Field: field, Computed: fmt.Sprint(computed), Expected: fmt.Sprint(expected)}
+			if i < len(txs) {
+				diff.TxHash = txs[i].Hash()
+			}
+			diffs = append(diffs, diff)
+		}
+		switch {
+		case i >= len(computed):
+			diff("receipt", "none", "present")
+			continue
+		case i >= len(expected):
+			diff("receipt", "present", "none")
+			continue
+		}
+		c, e := computed[i], expected[i]
+		if c.Type != e.Type {
+			diff("type", c.Type, e.Type)
+		}
+		if len(c.PostState) > 0 || len(e.PostState) > 0 {
+			if !bytes.Equal(c.PostState, e.PostState) {
+				diff("root", hexutil.Bytes(c.PostState), hexutil.Bytes(e.PostState))
+			}
+		} else if c.Status != e.Status {
+			diff("status", c.Status, e.Status)
+		}
+		if c.CumulativeGasUsed != e.CumulativeGasUsed {
+			diff("cumulativeGasUsed", c.CumulativeGasUsed, e.CumulativeGasUsed)
+		}
+		if c.GasUsed != e.GasUsed {
+			diff("gasUsed", c.GasUsed, e.GasUsed)
+		}
+		if c.Bloom != e.Bloom {
+			diff("logsBloom", hexutil.Bytes(c.Bloom[:]), hexutil.Bytes(e.Bloom[:]))
+		}
+		if len(c.Logs) != len(e.Logs) {
+			diff("logs", fmt.Sprintf("%d logs", len(c.Logs)), fmt.Sprintf("%d logs", len(e.Logs)))
+		}
+		for j := 0; j < min(len(c.Logs), len(e.Logs)); j++ {
+			cl, el := c.Logs[j], e.Logs[j]
+			if cl.Address != el.Address {
+				diff(fmt.Sprintf("logs[%d].address", j), cl.Address.Hex(), el.Address.Hex())
+			}
+			if !slices.Equal(cl.Topics, el.Topics) {
+				diff(fmt.Sprintf("logs[%d].topics", j), cl.Topics, el.Topics)
+			}
+			if !bytes.Equal(cl.Data, el.Data) {
+				diff(fmt.Sprintf("logs[%d].data", j), hexutil.Bytes(cl.Data), hexutil.Bytes(el.Data))
+			}
+		}
+	}
+	return diffs
+}
+
+// printReceiptDiffs writes the receipt differences to w, one per line.
+func printReceiptDiffs(w io.Writer, diffs []receiptDiff) {
+	for _, d := range diffs {
+		fmt.Fprintf(w, "receipt %d (%s): %s computed %s, expected %s\n", d.Index, d.TxHash.Hex(), d.Field, d.Computed, d.Expected)
+	}
+}
+
+// reportReceiptDiffs compares the computed receipts of a block whose receipt
+// root mismatches with the expected receipts in the given file, and writes the
+// differences to stderr. The expected receipts are checked against the receipt
+// root of the header first, to catch receipts of another block.
+func reportReceiptDiffs(path string, block *types.Block, computed types.Receipts) {
+	expected, err := loadReceipts(path)
+	if err != nil {
+		logger.Error("Failed to load expected receipts", "path", path, "err", err)
+		return
+	}
+	if root := types.DeriveSha(expected, trie.NewStackTrie(nil)); root != block.ReceiptHash() {
+		logger.Warn("Expected receipts do not match the receipt root of the header", "path", path, "root", root, "header", block.ReceiptHash())
+	}
+	diffs := diffReceipts(block, computed, expected)
+	if len(diffs) == 0 {
+		logger.Warn("Computed receipts match the expected receipts", "path", path)
+		return
+	}
+	printReceiptDiffs(os.Stderr, diffs)
+}
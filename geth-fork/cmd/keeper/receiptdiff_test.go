@@ -0,0 +1,85 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestDiffReceipts tests that differing consensus fields of receipts are
+// reported with the index of the receipt and the name of the field.
+func TestDiffReceipts(t *testing.T) {
+	txs := []*types.Transaction{types.NewTx(&types.LegacyTx{Nonce: 0}), types.NewTx(&types.LegacyTx{Nonce: 1})}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(types.Body{Transactions: txs})
+
+	receipt := func(status uint64, gas uint64, topic common.Hash) *types.Receipt {
+		r := &types.Receipt{Status: status, CumulativeGasUsed: gas, GasUsed: 21000, Logs: []*types.Log{{Address: common.Address{0x01}, Topics: []common.Hash{topic}}}}
+		r.Bloom = types.CreateBloom(r)
+		return r
+	}
+	expected := types.Receipts{receipt(1, 21000, common.Hash{0x01}), receipt(1, 42000, common.Hash{0x02})}
+	computed := types.Receipts{receipt(1, 21000, common.Hash{0x01}), receipt(0, 42000, common.Hash{0x03})}
+
+	if diffs := diffReceipts(block, expected, expected); len(diffs) != 0 {
+		t.Fatalf("identical receipts reported as different: %+v", diffs)
+	}
+	diffs := diffReceipts(block, computed, expected)
+	want := []string{"status", "logsBloom", "logs[0].topics"}
+	if len(diffs) != len(want) {
+		t.Fatalf("found %d differences, want %d: %+v", len(diffs), len(want), diffs)
+	}
+	for i, field := range want {
+		if diffs[i].Index != 1 || diffs[i].TxHash != txs[1].Hash() || diffs[i].Field != field {
+			t.Errorf("difference %d = %+v, want %s of receipt 1", i, diffs[i], field)
+		}
+	}
+	if diffs := diffReceipts(block, computed[:1], expected); len(diffs) != 1 || diffs[0].Field != "receipt" {
+		t.Errorf("missing receipt not reported: %+v", diffs)
+	}
+}
+
+// TestLoadReceipts tests loading expected receipts in the format returned by
+// eth_getBlockReceipts.
+func TestLoadReceipts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipts.json")
+	blob := `[{"type":"0x2","status":"0x1","cumulativeGasUsed":"0x5208","gasUsed":"0x5208","logs":[],` +
+		`"logsBloom":"0x` + common.Bytes2Hex(make([]byte, types.BloomByteLength)) + `",` +
+		`"transactionHash":"0x0000000000000000000000000000000000000000000000000000000000000001","transactionIndex":"0x0",` +
+		`"blockHash":"0x0000000000000000000000000000000000000000000000000000000000000002","blockNumber":"0x1","effectiveGasPrice":"0x1"}]`
+	if err := os.WriteFile(path, []byte(blob), 0644); err != nil {
+		t.Fatal(err)
+	}
+	receipts, err := loadReceipts(path)
+	if err != nil {
+		t.Fatalf("failed to load receipts: %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].Type != types.DynamicFeeTxType || receipts[0].Status != types.ReceiptStatusSuccessful || receipts[0].CumulativeGasUsed != 21000 {
+		t.Errorf("unexpected receipts %+v", receipts)
+	}
+	if err := os.WriteFile(path, []byte(`[{"status":"0x1"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadReceipts(path); err == nil {
+		t.Error("incomplete receipt accepted")
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// receiptCounter counts the receipts generated while executing a block. System
+// calls do not produce receipts and are not counted.
+type receiptCounter struct {
+	count int
+}
+
+// hooks returns the tracing hooks feeding the counter.
+func (c *receiptCounter) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxEnd: func(receipt *types.Receipt, err error) {
+			if receipt != nil {
+				c.count++
+			}
+		},
+	}
+}
+
+// verifyReceiptCount checks that execution generated exactly one receipt per
+// transaction of the block. Execution guarantees this by construction, so a
+// mismatch points to a change in the execution API or an anomaly induced by
+// the witness, and is reported as such rather than as a receipt root mismatch.
+func verifyReceiptCount(block *types.Block, receipts int) error {
+	if txs := len(block.Transactions()); receipts != txs {
+		return fmt.Errorf("execution generated %d receipts for %d transactions", receipts, txs)
+	}
+	return nil
+}
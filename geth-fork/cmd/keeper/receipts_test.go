@@ -0,0 +1,49 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestVerifyReceiptCount tests that receipts are counted from the transaction
+// hooks and checked against the transactions of the block.
+func TestVerifyReceiptCount(t *testing.T) {
+	var block types.Block
+	if err := rlp.DecodeBytes(makeTxBlock(t, 2), &block); err != nil {
+		t.Fatal(err)
+	}
+	counter := new(receiptCounter)
+	hooks := counter.hooks()
+	hooks.OnTxEnd(new(types.Receipt), nil)
+	hooks.OnTxEnd(nil, errors.New("transaction failed"))
+	if err := verifyReceiptCount(&block, counter.count); err == nil {
+		t.Fatal("missing receipt not detected")
+	}
+	hooks.OnTxEnd(new(types.Receipt), nil)
+	if err := verifyReceiptCount(&block, counter.count); err != nil {
+		t.Fatalf("receipt count rejected: %v", err)
+	}
+	hooks.OnTxEnd(new(types.Receipt), nil)
+	if err := verifyReceiptCount(&block, counter.count); err == nil {
+		t.Fatal("extra receipt not detected")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// executionPanic is the error returned in place of a panic raised while
+// executing a block. Execution is deep inside go-ethereum and trusts that the
+// block and witness are consistent, which hand-crafted payloads need not be,
+// such as a block without transactions whose witness was built for a full one.
+type executionPanic struct {
+	value any // Value the execution panicked with
+}
+
+func (e *executionPanic) Error() string {
+	return fmt.Sprintf("execution panicked: %v", e.value)
+}
+
+// recoverExecution runs the given block execution, converting a panic into an
+// executionPanic error, so that a malformed payload fails validation instead
+// of crashing the keeper. The stack trace of the panic is logged at debug level.
+func recoverExecution(execute func() (common.Hash, common.Hash, error)) (stateRoot common.Hash, receiptRoot common.Hash, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			logger.Debug("Recovered from execution panic", "err", value, "stack", string(debug.Stack()))
+			stateRoot, receiptRoot, err = common.Hash{}, common.Hash{}, &executionPanic{value}
+		}
+	}()
+	return execute()
+}
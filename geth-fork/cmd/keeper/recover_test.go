@@ -0,0 +1,57 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// TestRecoverExecution tests that a panicking execution is turned into an error
+// carrying the panic, with or without a deadline, and that the stack trace of
+// the panic is logged at debug level.
+func TestRecoverExecution(t *testing.T) {
+	var out bytes.Buffer
+	handler, err := newLogHandler(&out, "debug", logFormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(previous log.Logger) { logger = previous }(logger)
+	logger = log.NewLogger(handler)
+
+	crash := func() (common.Hash, common.Hash, error) {
+		var txs []byte
+		return common.Hash{txs[0]}, common.Hash{}, nil
+	}
+	for _, deadline := range []time.Time{{}, time.Now().Add(time.Minute)} {
+		out.Reset()
+		_, _, err := runWithDeadline(deadline, crash)
+		var panicErr *executionPanic
+		if !errors.As(err, &panicErr) || !strings.Contains(err.Error(), "index out of range") {
+			t.Errorf("deadline %v: error %v, want recovered panic", deadline, err)
+		}
+		if !strings.Contains(out.String(), "Recovered from execution panic") || !strings.Contains(out.String(), "recover_test.go") {
+			t.Errorf("deadline %v: panic stack not logged: %q", deadline, out.String())
+		}
+	}
+}
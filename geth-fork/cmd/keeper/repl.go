@@ -0,0 +1,244 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// replHelp lists the commands understood by the REPL.
+const replHelp = `Commands:
+  load <path>       load a payload from a file (encoded as per --format)
+  header            show the block header
+  txs               list the transactions of the block
+  witness-stats     show the size of the witness
+  account <addr>    look up an account in the witness pre-state
+  validate          run the full validation pipeline
+  diff-roots        compare the computed roots with the header
+  help              show this help
+  quit              leave the REPL`
+
+// repl is an interactive session over a single payload.
+type repl struct {
+	out     io.Writer
+	input   []byte   // Decoded raw payload, as passed to the validation pipeline
+	payload *Payload // Payload decoded from input, nil if none is loaded
+}
+
+// runRepl runs the REPL subcommand. The payload is loaded from the file given
+// as argument, or from the input selected by --input if there is none.
+func runRepl(args []string) int {
+	r := &repl{out: os.Stdout}
+	var (
+		input []byte
+		err   error
+	)
+	if len(args) > 0 {
+		input, err = readInputFile(args[0])
+	} else {
+		input, err = loadInput(*inputPath)
+	}
+	if err == nil {
+		err = r.load(input)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load payload: %v\n", err)
+	}
+	r.run(os.Stdin)
+	return ExitSuccess
+}
+
+// run reads commands line by line until the input is exhausted or the session
+// is ended with quit.
+func (r *repl) run(in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(r.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return
+		}
+		if err := r.exec(fields[0], fields[1:]); err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+	}
+}
+
+// exec executes a single command.
+func (r *repl) exec(cmd string, args []string) error {
+	switch cmd {
+	case "help":
+		fmt.Fprintln(r.out, replHelp)
+		return nil
+	case "load":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: load <path>")
+		}
+		input, err := readInputFile(args[0])
+		if err != nil {
+			return err
+		}
+		return r.load(input)
+	}
+	if r.payload == nil {
+		return fmt.Errorf("no payload loaded")
+	}
+	switch cmd {
+	case "header":
+		r.header()
+	case "txs":
+		r.txs()
+	case "witness-stats":
+		r.witnessStats()
+	case "account":
+		if len(args) != 1 || !common.IsHexAddress(args[0]) {
+			return fmt.Errorf("usage: account <address>")
+		}
+		return r.account(common.HexToAddress(args[0]))
+	case "validate":
+		result := process(r.input)
+		if result.Valid {
+			fmt.Fprintf(r.out, "valid (block %d, %s)\n", result.Number, result.ActiveFork)
+		} else {
+			fmt.Fprintf(r.out, "invalid (exit code %d): %s\n", result.ExitCode, result.Error)
+		}
+	case "diff-roots":
+		result := process(r.input)
+		if result.StateRoot == (common.Hash{}) {
+			return fmt.Errorf("execution did not complete: %s", result.Error)
+		}
+		block := r.payload.Block
+		fmt.Fprintf(r.out, "state root:   header %x computed %x %s\n", block.Root(), result.StateRoot, matchString(block.Root() == result.StateRoot))
+		fmt.Fprintf(r.out, "receipt root: header %x computed %x %s\n", block.ReceiptHash(), result.ReceiptRoot, matchString(block.ReceiptHash() == result.ReceiptRoot))
+	default:
+		return fmt.Errorf("unknown command %q, try help", cmd)
+	}
+	return nil
+}
+
+// load decodes the payload, replacing the current one if decoding succeeds.
+func (r *repl) load(input []byte) error {
+	input, _, err := decodeInput(input, *inputFormat)
+	if err != nil {
+		return err
+	}
+	if err := validateInput(input); err != nil {
+		return err
+	}
+	payload := new(Payload)
+	if err := decodeRLP(input, payload); err != nil {
+		return describeDecodeError(input, err)
+	}
+	if err := validatePayload(payload); err != nil {
+		return err
+	}
+	r.input, r.payload = input, payload
+	fmt.Fprintf(r.out, "loaded block %d (%x) on chain %d\n", payload.Block.NumberU64(), payload.Block.Hash(), payload.ChainID)
+	return nil
+}
+
+func (r *repl) header() {
+	h := r.payload.Block.Header()
+	fmt.Fprintf(r.out, "number:       %d\n", h.Number)
+	fmt.Fprintf(r.out, "hash:         %x\n", h.Hash())
+	fmt.Fprintf(r.out, "parent:       %x\n", h.ParentHash)
+	fmt.Fprintf(r.out, "time:         %d\n", h.Time)
+	fmt.Fprintf(r.out, "coinbase:     %s\n", h.Coinbase.Hex())
+	fmt.Fprintf(r.out, "gas:          %d / %d\n", h.GasUsed, h.GasLimit)
+	if h.BaseFee != nil {
+		fmt.Fprintf(r.out, "base fee:     %d\n", h.BaseFee)
+	}
+	fmt.Fprintf(r.out, "state root:   %x\n", h.Root)
+	fmt.Fprintf(r.out, "tx root:      %x\n", h.TxHash)
+	fmt.Fprintf(r.out, "receipt root: %x\n", h.ReceiptHash)
+}
+
+func (r *repl) txs() {
+	signer := types.LatestSignerForChainID(nil)
+	if config, err := getChainConfig(r.payload.ChainID); err == nil {
+		signer = types.MakeSigner(config, r.payload.Block.Number(), r.payload.Block.Time())
+	}
+	for i, tx := range r.payload.Block.Transactions() {
+		to := "create"
+		if tx.To() != nil {
+			to = tx.To().Hex()
+		}
+		from := "?"
+		if sender, err := types.Sender(signer, tx); err == nil {
+			from = sender.Hex()
+		}
+		fmt.Fprintf(r.out, "%d: %x type %d from %s to %s value %d gas %d\n", i, tx.Hash(), tx.Type(), from, to, tx.Value(), tx.Gas())
+	}
+	fmt.Fprintf(r.out, "%d transactions\n", len(r.payload.Block.Transactions()))
+}
+
+func (r *repl) witnessStats() {
+	w := r.payload.Witness
+	var codes, nodes int
+	for code := range w.Codes {
+		codes += len(code)
+	}
+	for node := range w.State {
+		nodes += len(node)
+	}
+	fmt.Fprintf(r.out, "headers:     %d\n", len(w.Headers))
+	fmt.Fprintf(r.out, "codes:       %d (%d bytes)\n", len(w.Codes), codes)
+	fmt.Fprintf(r.out, "state nodes: %d (%d bytes)\n", len(w.State), nodes)
+	fmt.Fprintf(r.out, "total size:  %d bytes\n", witnessSize(w))
+}
+
+func (r *repl) account(addr common.Address) error {
+	state, err := newWitnessState(r.payload.Witness)
+	if err != nil {
+		return err
+	}
+	acc, err := state.account(addr)
+	if err != nil {
+		return err
+	}
+	if acc == nil {
+		fmt.Fprintf(r.out, "%s does not exist\n", addr.Hex())
+		return nil
+	}
+	fmt.Fprintf(r.out, "nonce:        %d\n", acc.Nonce)
+	fmt.Fprintf(r.out, "balance:      %d\n", acc.Balance)
+	fmt.Fprintf(r.out, "storage root: %x\n", acc.Root)
+	fmt.Fprintf(r.out, "code hash:    %x\n", acc.CodeHash)
+	return nil
+}
+
+// matchString renders the outcome of a comparison.
+func matchString(match bool) string {
+	if match {
+		return "(match)"
+	}
+	return "(MISMATCH)"
+}
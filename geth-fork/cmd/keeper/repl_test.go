@@ -0,0 +1,71 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestRepl tests a REPL session over a payload.
+func TestRepl(t *testing.T) {
+	parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0), Root: types.EmptyRootHash}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), ParentHash: parent.Hash()}, nil, nil, trie.NewStackTrie(nil))
+	witness, err := stateless.NewWitness(block.Header(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness.Headers = []*types.Header{parent}
+	input, err := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: block, Witness: witness})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	r := &repl{out: &out}
+	r.run(strings.NewReader("header\n"))
+	if !strings.Contains(out.String(), "no payload loaded") {
+		t.Errorf("command without payload not rejected: %q", out.String())
+	}
+	if err := r.load(input); err != nil {
+		t.Fatalf("failed to load payload: %v", err)
+	}
+	out.Reset()
+	r.run(strings.NewReader("header\ntxs\nwitness-stats\naccount " + common.Address{0x01}.Hex() + "\nbogus\nquit\nheader\n"))
+
+	for _, want := range []string{
+		"number:       1",
+		"0 transactions",
+		"headers:     1",
+		"does not exist",
+		`unknown command "bogus"`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Count(out.String(), "number:") != 1 {
+		t.Error("commands executed after quit")
+	}
+}
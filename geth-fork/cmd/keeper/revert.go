@@ -0,0 +1,101 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// revertInfo describes a transaction of the block that failed during execution.
+type revertInfo struct {
+	Tx     int         `json:"tx"`
+	Hash   common.Hash `json:"hash"`
+	Reason string      `json:"reason"`
+}
+
+// revertTracer records the failure reason of every transaction whose top-level
+// call did not succeed. Failed transactions are still part of a valid block, so
+// this is purely a diagnostic and has no effect on validation.
+type revertTracer struct {
+	reverts []revertInfo
+
+	tx     int         // Index of the running transaction
+	hash   common.Hash // Hash of the running transaction
+	inTx   bool        // Whether a transaction is running, as opposed to a system call
+	output []byte      // Return data of the top-level call
+	err    error       // Error of the top-level call
+}
+
+func newRevertTracer() *revertTracer {
+	return &revertTracer{tx: -1}
+}
+
+// hooks returns the tracing hooks feeding the tracer.
+func (t *revertTracer) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: t.onTxStart,
+		OnTxEnd:   t.onTxEnd,
+		OnExit:    t.onExit,
+	}
+}
+
+func (t *revertTracer) onTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.tx++
+	t.hash = tx.Hash()
+	t.inTx = true
+	t.output, t.err = nil, nil
+}
+
+func (t *revertTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if !t.inTx || depth != 0 {
+		return
+	}
+	t.output = common.CopyBytes(output)
+	t.err = err
+}
+
+func (t *revertTracer) onTxEnd(receipt *types.Receipt, err error) {
+	t.inTx = false
+	if receipt == nil || receipt.Status != types.ReceiptStatusFailed {
+		return
+	}
+	t.reverts = append(t.reverts, revertInfo{
+		Tx:     t.tx,
+		Hash:   t.hash,
+		Reason: revertReason(t.output, t.err),
+	})
+}
+
+// revertReason decodes the return data of a failed call. Standard Error(string)
+// and Panic(uint256) payloads are unpacked, other return data is reported as
+// hex, and failures without return data (e.g. out of gas) report the error.
+func revertReason(output []byte, err error) string {
+	if reason, unpackErr := abi.UnpackRevert(output); unpackErr == nil {
+		return reason
+	}
+	if len(output) > 0 {
+		return hexutil.Encode(output)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return "unknown"
+}
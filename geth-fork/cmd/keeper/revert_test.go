@@ -0,0 +1,66 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// TestRevertTracer tests that only failed transactions are recorded, with
+// their return data decoded into a revert reason.
+func TestRevertTracer(t *testing.T) {
+	// Error("valve locked"), as emitted by Solidity's revert("valve locked")
+	errorData := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000c" +
+		"76616c7665206c6f636b65640000000000000000000000000000000000000000")
+
+	tracer := newRevertTracer()
+	hooks := tracer.hooks()
+	run := func(output []byte, err error, status uint64) {
+		hooks.OnTxStart(nil, types.NewTx(&types.LegacyTx{}), common.Address{})
+		hooks.OnExit(1, []byte{0xff}, 0, nil, false) // nested frames are ignored
+		hooks.OnExit(0, output, 0, err, err != nil)
+		hooks.OnTxEnd(&types.Receipt{Status: status}, nil)
+	}
+	run(nil, nil, types.ReceiptStatusSuccessful)
+	run(errorData, vm.ErrExecutionReverted, types.ReceiptStatusFailed)
+	run([]byte{0xde, 0xad}, vm.ErrExecutionReverted, types.ReceiptStatusFailed)
+	run(nil, vm.ErrOutOfGas, types.ReceiptStatusFailed)
+
+	want := []struct {
+		tx     int
+		reason string
+	}{
+		{1, "valve locked"},
+		{2, "0xdead"},
+		{3, vm.ErrOutOfGas.Error()},
+	}
+	if len(tracer.reverts) != len(want) {
+		t.Fatalf("recorded %d reverts, want %d: %v", len(tracer.reverts), len(want), tracer.reverts)
+	}
+	for i, w := range want {
+		if got := tracer.reverts[i]; got.Tx != w.tx || got.Reason != w.reason {
+			t.Errorf("revert %d = (tx %d, %q), want (tx %d, %q)", i, got.Tx, got.Reason, w.tx, w.reason)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// deriveSeed derives the seed used to sample or shuffle a batch. An explicit
+// seed string is hashed as is; otherwise the seed is the Keccak256 of the
+// concatenated Keccak256 hashes of the payloads, so that every host processing
+// the same batch arrives at the same seed without coordination.
+func deriveSeed(payloads [][]byte, seed string) common.Hash {
+	if seed != "" {
+		return crypto.Keccak256Hash([]byte(seed))
+	}
+	hashes := make([][]byte, len(payloads))
+	for i, payload := range payloads {
+		hashes[i] = crypto.Keccak256(payload)
+	}
+	return crypto.Keccak256Hash(hashes...)
+}
+
+// sampled reports whether the payload at the given position of a batch is part
+// of the sample of the given rate. The decision only depends on the seed and
+// the position, making the selected subset identical across runs and hosts.
+func sampled(seed common.Hash, index int, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	digest := crypto.Keccak256(seed[:], binary.BigEndian.AppendUint64(nil, uint64(index)))
+	return float64(binary.BigEndian.Uint64(digest)) < rate*math.MaxUint64
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestDeriveSeed tests that seeds are derived deterministically from either
+// the payloads or an explicit seed string.
+func TestDeriveSeed(t *testing.T) {
+	payloads := [][]byte{{0xc0}, {0xc1, 0x80}}
+
+	want := crypto.Keccak256Hash(crypto.Keccak256([]byte{0xc0}), crypto.Keccak256([]byte{0xc1, 0x80}))
+	if seed := deriveSeed(payloads, ""); seed != want {
+		t.Errorf("derived seed = %x, want %x", seed, want)
+	}
+	if seed := deriveSeed(payloads[:1], ""); seed == want {
+		t.Error("different batches derived the same seed")
+	}
+	if seed := deriveSeed(payloads, "audit-2025"); seed != crypto.Keccak256Hash([]byte("audit-2025")) {
+		t.Errorf("explicit seed not honoured: %x", seed)
+	}
+}
+
+// TestSampled tests that sampling is reproducible and selects roughly the
+// requested fraction of a batch.
+func TestSampled(t *testing.T) {
+	seed := deriveSeed(nil, "seed")
+
+	var selected int
+	for i := 0; i < 10000; i++ {
+		if sampled(seed, i, 0.1) != sampled(seed, i, 0.1) {
+			t.Fatalf("sampling of payload %d is not deterministic", i)
+		}
+		if sampled(seed, i, 0.1) {
+			selected++
+		}
+		if !sampled(seed, i, 1) {
+			t.Fatalf("payload %d not selected at rate 1", i)
+		}
+	}
+	if selected < 800 || selected > 1200 {
+		t.Errorf("selected %d of 10000 payloads at rate 0.1", selected)
+	}
+}
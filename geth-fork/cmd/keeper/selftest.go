@@ -0,0 +1,83 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// examplePayload encodes the bundled Hoodi block and its witness as a payload.
+func examplePayload() ([]byte, error) {
+	if len(blockRlp) == 0 || len(witnessRlp) == 0 {
+		return nil, errors.New("no fixture bundled in this build")
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(blockRlp, block); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture block: %v", err)
+	}
+	witness := new(stateless.Witness)
+	if err := rlp.DecodeBytes(witnessRlp, witness); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture witness: %v", err)
+	}
+	return stateless.EncodePayload(params.HoodiChainConfig.ChainID.Uint64(), block, witness)
+}
+
+// runSelfTest runs the self-test subcommand: it validates the bundled Hoodi
+// block through the full pipeline, honouring the command line flags, and
+// reports whether the computed roots match the header. The roots are compared
+// here as well, so that the self-test only passes if the block was executed.
+// The exit code is that of the failed validation, or ExitValidationFailed if
+// validation passed without computing the roots of the header.
+func runSelfTest(args []string, out io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "Error: self-test takes no arguments")
+		fmt.Fprintln(os.Stderr, "Usage: self-test")
+		return 2
+	}
+	input, err := examplePayload()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "self-test: %v\n", err)
+		return ExitInvalidInput
+	}
+	start := time.Now()
+	result := process(input)
+	elapsed := time.Since(start)
+
+	fmt.Fprintf(out, "block:        %d (%s) on chain %d\n", result.Number, result.Hash.Hex(), result.ChainID)
+	fmt.Fprintf(out, "state root:   %x %x %s\n", result.StateRoot, result.ExpectedStateRoot, matchString(result.StateRoot == result.ExpectedStateRoot))
+	fmt.Fprintf(out, "receipt root: %x %x %s\n", result.ReceiptRoot, result.ExpectedReceiptRoot, matchString(result.ReceiptRoot == result.ExpectedReceiptRoot))
+	switch {
+	case !result.Valid:
+		fmt.Fprintf(out, "self-test FAILED: %s\n", result.Error)
+		return result.ExitCode
+	case result.StateRoot != result.ExpectedStateRoot || result.ReceiptRoot != result.ExpectedReceiptRoot:
+		fmt.Fprintln(out, "self-test FAILED: the block was not executed or not all roots were compared")
+		return ExitValidationFailed
+	default:
+		fmt.Fprintf(out, "self-test passed in %v\n", elapsed.Round(time.Microsecond))
+		return ExitSuccess
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !ziren
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestSelfTest tests that the bundled fixture passes the self-test, and that
+// the self-test fails if the block is not executed.
+func TestSelfTest(t *testing.T) {
+	var out bytes.Buffer
+	if code := runSelfTest(nil, &out); code != ExitSuccess {
+		t.Fatalf("self-test exit code = %d, want %d:\n%s", code, ExitSuccess, out.String())
+	}
+	if !strings.Contains(out.String(), "self-test passed") || strings.Contains(out.String(), "MISMATCH") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	defer func(decode bool) { *decodeOnly = decode }(*decodeOnly)
+	*decodeOnly = true
+	out.Reset()
+	if code := runSelfTest(nil, &out); code != ExitValidationFailed || !strings.Contains(out.String(), "FAILED") {
+		t.Errorf("decode-only self-test exit code = %d, want %d:\n%s", code, ExitValidationFailed, out.String())
+	}
+	if code := runSelfTest([]string{"extra"}, &out); code != 2 {
+		t.Errorf("self-test with arguments exit code = %d, want 2", code)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// runServe runs the serve subcommand: it listens on the Unix domain socket at
+// the given path and validates the payloads sent over it until terminated by
+// SIGINT or SIGTERM, which exit with ExitInterrupted, or killed by a second one.
+// A stale socket left behind by a previous server is replaced.
+func runServe(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: serve requires the path of the socket to listen on")
+		fmt.Fprintln(os.Stderr, "Usage: serve <socket>")
+		return 2
+	}
+	path := args[0]
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		logger.Error("Failed to listen", "path", path, "err", err)
+		return ExitInvalidInput
+	}
+	defer os.Remove(path)
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	logger.Info("Serving payload validation", "path", path)
+	serve(ctx, listener)
+	logger.Info("Server stopped", "path", path)
+	if ctx.Err() != nil {
+		return ExitInterrupted
+	}
+	return ExitSuccess
+}
+
+// serve accepts connections on the listener and validates the payloads sent
+// over them until the context is cancelled. Every request is a payload, and
+// every response its JSON result, each prefixed by its length as a 4 byte
+// big-endian integer, like the payloads of a batch. Connections are served
+// concurrently, but payloads are validated one at a time, so that memory stays
+// bounded by the largest payload. On cancellation, the listener is closed and
+// the payload being validated, if any, is completed and answered before serve
+// returns; requests not fully received by then are dropped.
+func serve(ctx context.Context, listener net.Listener) {
+	var (
+		validating sync.Mutex
+		handlers   sync.WaitGroup
+		lock       sync.Mutex
+		conns      = make(map[net.Conn]struct{})
+		closing    bool
+	)
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+
+		// Unblock connections waiting for a request; responses in the making
+		// are still written.
+		lock.Lock()
+		defer lock.Unlock()
+		closing = true
+		for conn := range conns {
+			conn.SetReadDeadline(time.Now())
+		}
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("Failed to accept connection", "err", err)
+			}
+			break
+		}
+		lock.Lock()
+		if closing {
+			lock.Unlock()
+			conn.Close()
+			break
+		}
+		conns[conn] = struct{}{}
+		lock.Unlock()
+
+		handlers.Add(1)
+		go func() {
+			defer handlers.Done()
+			defer func() {
+				lock.Lock()
+				delete(conns, conn)
+				lock.Unlock()
+				conn.Close()
+			}()
+			serveConn(conn, &validating)
+		}()
+	}
+	handlers.Wait()
+}
+
+// serveConn answers the requests sent over a connection until it is closed or
+// a request cannot be read. Payloads are validated while holding the given
+// lock.
+func serveConn(conn net.Conn, validating *sync.Mutex) {
+	var (
+		r = bufio.NewReader(conn)
+		w = bufio.NewWriter(conn)
+	)
+	for {
+		var result *Result
+		input, err := readFrame(r)
+		switch {
+		case errors.Is(err, errRequestTooLarge):
+			result = (&Result{Stage: stageDecode, Build: currentBuild()}).fail(ExitInvalidInput, "input validation failed: %v", err)
+		case err != nil:
+			if !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrDeadlineExceeded) {
+				logger.Warn("Failed to read request", "err", err)
+			}
+			return
+		default:
+			validating.Lock()
+			result = process(input)
+
+			// With the garbage collector disabled, a long-running server has to
+			// collect the garbage of every payload itself.
+			if *gcPercent < 0 {
+				runtime.GC()
+			}
+			validating.Unlock()
+		}
+		logResult(result)
+
+		if err := writeFrame(w, result); err != nil {
+			logger.Warn("Failed to write response", "err", err)
+			return
+		}
+	}
+}
+
+// errRequestTooLarge is returned by readFrame for a request exceeding the
+// maximum input size.
+var errRequestTooLarge = errors.New("input exceeds maximum size")
+
+// readFrame reads a length-prefixed request. A request exceeding the maximum
+// input size is skipped without being held in memory, and errRequestTooLarge
+// returned, so that the connection can go on with the next request.
+func readFrame(r io.Reader) ([]byte, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(prefix[:])
+	if uint64(size) > uint64(*maxInputSize) {
+		if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w (%d > %d)", errRequestTooLarge, size, *maxInputSize)
+	}
+	input := make([]byte, size)
+	if _, err := io.ReadFull(r, input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// writeFrame writes the result as a length-prefixed JSON response.
+func writeFrame(w *bufio.Writer, result *Result) error {
+	blob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	w.Write(binary.BigEndian.AppendUint32(nil, uint32(len(blob))))
+	w.Write(blob)
+	return w.Flush()
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestServe tests that payloads sent over a connection are answered in order,
+// and that the server shuts down on cancellation despite idle connections.
+func TestServe(t *testing.T) {
+	defer func(size int) { *maxInputSize = size }(*maxInputSize)
+
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "keeper.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		serve(ctx, listener)
+		close(done)
+	}()
+
+	good := makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash)
+	*maxInputSize = len(good)
+
+	conn, err := net.Dial("unix", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write(makeBatch(good, []byte{0x05}, make([]byte, len(good)+1), good)); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	for i, want := range []int{ExitStateRootMismatch, ExitInvalidInput, ExitInvalidInput, ExitStateRootMismatch} {
+		var prefix [4]byte
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			t.Fatalf("response %d: %v", i, err)
+		}
+		blob := make([]byte, binary.BigEndian.Uint32(prefix[:]))
+		if _, err := io.ReadFull(r, blob); err != nil {
+			t.Fatalf("response %d: %v", i, err)
+		}
+		var result Result
+		if err := json.Unmarshal(blob, &result); err != nil {
+			t.Fatalf("response %d: invalid JSON result: %v", i, err)
+		}
+		if result.ExitCode != want {
+			t.Errorf("response %d: exit code = %d, want %d", i, result.ExitCode, want)
+		}
+		if i == 2 && !strings.Contains(result.Error, "exceeds maximum size") {
+			t.Errorf("response %d: error %q does not report the oversized request", i, result.Error)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	if _, err := net.Dial("unix", listener.Addr().String()); err == nil {
+		t.Error("server still accepting connections after shutdown")
+	}
+}
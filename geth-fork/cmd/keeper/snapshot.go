@@ -0,0 +1,140 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// loadSnapshot reads a state snapshot in the JSON format produced by `geth dump`
+// and rebuilds it in memory. The rebuilt state must hash to the expected root,
+// which guards against partial dumps and dumps of the wrong block.
+func loadSnapshot(path string, root common.Hash) (*state.StateDB, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var dump state.Dump
+	if err := json.Unmarshal(blob, &dump); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %v", err)
+	}
+	statedb, have, err := buildSnapshot(&dump)
+	if err != nil {
+		return nil, err
+	}
+	if have != root {
+		return nil, fmt.Errorf("snapshot state root %x does not match parent state root %x", have, root)
+	}
+	return statedb, nil
+}
+
+// loadGenesisAlloc reads a genesis allocation in the JSON format of the alloc
+// section of a genesis file and builds the state it describes in memory, as the
+// pre-state of a synthetic block. Like a snapshot, the state must hash to the
+// expected root.
+func loadGenesisAlloc(path string, root common.Hash) (*state.StateDB, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var alloc types.GenesisAlloc
+	if err := json.Unmarshal(blob, &alloc); err != nil {
+		return nil, fmt.Errorf("invalid genesis allocation: %v", err)
+	}
+	statedb, have, err := buildAllocState(alloc)
+	if err != nil {
+		return nil, err
+	}
+	if have != root {
+		return nil, fmt.Errorf("genesis allocation state root %x does not match parent state root %x", have, root)
+	}
+	return statedb, nil
+}
+
+// buildAllocState populates a fresh in-memory state with the accounts of a
+// genesis allocation and returns it along with its root.
+func buildAllocState(alloc types.GenesisAlloc) (*state.StateDB, common.Hash, error) {
+	db := state.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.HashDefaults), nil)
+	statedb, err := state.New(common.Hash{}, db)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	for addr, account := range alloc {
+		if account.Balance != nil {
+			statedb.SetBalance(addr, uint256.MustFromBig(account.Balance), tracing.BalanceChangeUnspecified)
+		}
+		statedb.SetNonce(addr, account.Nonce, tracing.NonceChangeUnspecified)
+		if len(account.Code) > 0 {
+			statedb.SetCode(addr, account.Code, tracing.CodeChangeUnspecified)
+		}
+		for slot, value := range account.Storage {
+			statedb.SetState(addr, slot, value)
+		}
+	}
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	statedb, err = state.New(root, db)
+	return statedb, root, err
+}
+
+// buildSnapshot populates a fresh in-memory state with the accounts of a dump
+// and returns it along with its root.
+func buildSnapshot(dump *state.Dump) (*state.StateDB, common.Hash, error) {
+	db := state.NewDatabase(triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.HashDefaults), nil)
+	statedb, err := state.New(common.Hash{}, db)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	for key, account := range dump.Accounts {
+		if !common.IsHexAddress(key) {
+			return nil, common.Hash{}, fmt.Errorf("snapshot account %q has no address", key)
+		}
+		addr := common.HexToAddress(key)
+
+		balance, ok := new(big.Int).SetString(account.Balance, 10)
+		if !ok {
+			return nil, common.Hash{}, fmt.Errorf("snapshot account %s has invalid balance %q", addr.Hex(), account.Balance)
+		}
+		statedb.SetBalance(addr, uint256.MustFromBig(balance), tracing.BalanceChangeUnspecified)
+		statedb.SetNonce(addr, account.Nonce, tracing.NonceChangeUnspecified)
+		if len(account.Code) > 0 {
+			statedb.SetCode(addr, account.Code, tracing.CodeChangeUnspecified)
+		}
+		for slot, value := range account.Storage {
+			statedb.SetState(addr, slot, common.HexToHash(value))
+		}
+	}
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	statedb, err = state.New(root, db)
+	return statedb, root, err
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestStateSnapshot tests stateful validation against a snapshot and its
+// cross-check with the witness, using a Frontier block without transactions.
+func TestStateSnapshot(t *testing.T) {
+	funded := &state.Dump{Accounts: map[string]state.DumpAccount{
+		common.Address{0xaa}.Hex(): {Balance: "1000", Nonce: 1, Storage: map[common.Hash]string{{0x01}: "02"}},
+	}}
+	_, fundedRoot, err := buildSnapshot(funded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := &state.Dump{Accounts: map[string]state.DumpAccount{}}
+
+	tests := []struct {
+		dump       *state.Dump
+		parentRoot common.Hash
+		wantCode   int
+		wantErr    string
+	}{
+		// Both executions agree, the zero header root is then reported
+		{empty, types.EmptyRootHash, ExitStateRootMismatch, "root mismatch"},
+		// The snapshot is not the pre-state of the block
+		{funded, types.EmptyRootHash, ExitInvalidInput, "does not match parent state root"},
+		// The witness lacks the trie nodes of the pre-state, caught before
+		// either execution
+		{funded, fundedRoot, ExitWitnessIncomplete, "missing the parent state root node"},
+	}
+	defer func(path string) { *stateSnapshot = path }(*stateSnapshot)
+	for i, tt := range tests {
+		path := filepath.Join(t.TempDir(), "snapshot.json")
+		blob, _ := json.Marshal(tt.dump)
+		if err := os.WriteFile(path, blob, 0644); err != nil {
+			t.Fatal(err)
+		}
+		*stateSnapshot = path
+
+		parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: tt.parentRoot, GasLimit: 5000}
+		header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash(), Coinbase: common.Address{0xcb}, GasLimit: 5000}
+		block := types.NewBlock(header, nil, nil, trie.NewStackTrie(nil))
+		witness, _ := stateless.NewWitness(header, nil)
+		witness.Headers = []*types.Header{parent}
+		input, _ := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: block, Witness: witness})
+
+		result := process(input)
+		if result.ExitCode != tt.wantCode || !strings.Contains(result.Error, tt.wantErr) {
+			t.Errorf("test %d: result %d %q, want %d %q", i, result.ExitCode, result.Error, tt.wantCode, tt.wantErr)
+		}
+	}
+}
+
+// TestGenesisAlloc tests execution against a pre-state built from a genesis
+// allocation, with a witness that only carries the parent header.
+func TestGenesisAlloc(t *testing.T) {
+	alloc := types.GenesisAlloc{
+		common.Address{0xaa}: {Balance: big.NewInt(1000), Nonce: 1, Storage: map[common.Hash]common.Hash{{0x01}: {0x02}}},
+		common.Address{0xbb}: {Balance: big.NewInt(0), Code: []byte{0x60, 0x00}},
+	}
+	_, root, err := buildAllocState(alloc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "alloc.json")
+	blob, _ := json.Marshal(alloc)
+	if err := os.WriteFile(path, blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+	makePayload := func(parentRoot common.Hash) []byte {
+		parent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Root: parentRoot, GasLimit: 5000}
+		header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: parent.Hash(), Coinbase: common.Address{0xcb}, GasLimit: 5000}
+		witness, _ := stateless.NewWitness(header, nil)
+		witness.Headers = []*types.Header{parent}
+		input, _ := rlp.EncodeToBytes(&Payload{ChainID: 1, Block: types.NewBlock(header, nil, nil, trie.NewStackTrie(nil)), Witness: witness})
+		return input
+	}
+	tests := []struct {
+		alloc      string
+		parentRoot common.Hash
+		wantCode   int
+		wantErr    string
+	}{
+		// The block is executed, the zero header root is then reported
+		{path, root, ExitStateRootMismatch, "root mismatch"},
+		// The allocation is not the pre-state of the block
+		{path, types.EmptyRootHash, ExitInvalidInput, "does not match parent state root"},
+		// Without the allocation, the witness lacks the pre-state
+		{"", root, ExitWitnessIncomplete, "missing the parent state root node"},
+	}
+	defer func(path string) { *genesisAlloc = path }(*genesisAlloc)
+	for i, tt := range tests {
+		*genesisAlloc = tt.alloc
+		result := process(makePayload(tt.parentRoot))
+		if result.ExitCode != tt.wantCode || !strings.Contains(result.Error, tt.wantErr) {
+			t.Errorf("test %d: result %d %q, want %d %q", i, result.ExitCode, result.Error, tt.wantCode, tt.wantErr)
+		}
+		if tt.wantCode == ExitStateRootMismatch && (result.StateRoot == common.Hash{} || result.StateRoot == root) {
+			t.Errorf("test %d: unexpected computed state root %x", i, result.StateRoot)
+		}
+	}
+}
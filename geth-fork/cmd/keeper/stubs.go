@@ -18,6 +18,10 @@
 
 package main
 
+// platformInput reports whether getInput provides a payload. Without it, the
+// payload is read from stdin unless a file is given.
+const platformInput = false
+
 // getInput is a stub implementation for when no platform-specific build tags are set.
 // This allows golangci-lint to typecheck the code without errors.
 // The actual implementations are provided in platform-specific files.
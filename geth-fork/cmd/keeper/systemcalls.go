@@ -0,0 +1,147 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// beaconRootsBufferLength is the size of the ring buffer of the EIP-4788
+// beacon roots contract.
+const beaconRootsBufferLength = 8191
+
+// requestCountSlot is the storage slot of the EIP-7002 and EIP-7251 request
+// queue contracts holding the number of requests added in the current block.
+// The end-of-block system call resets it to zero.
+var requestCountSlot = common.BigToHash(common.Big1)
+
+// systemCallTracer records the last value written to every storage slot of the
+// system contracts during the execution of a block, covering both the system
+// calls made outside of transactions and user transactions calling into them.
+type systemCallTracer struct {
+	writes map[common.Address]map[common.Hash]common.Hash
+}
+
+func newSystemCallTracer() *systemCallTracer {
+	return &systemCallTracer{writes: make(map[common.Address]map[common.Hash]common.Hash)}
+}
+
+// hooks returns the tracing hooks feeding the tracer.
+func (t *systemCallTracer) hooks() *tracing.Hooks {
+	return &tracing.Hooks{OnStorageChange: t.onStorageChange}
+}
+
+func (t *systemCallTracer) onStorageChange(addr common.Address, slot common.Hash, prev, new common.Hash) {
+	switch addr {
+	case params.BeaconRootsAddress, params.HistoryStorageAddress, params.WithdrawalQueueAddress, params.ConsolidationQueueAddress:
+	default:
+		return
+	}
+	if t.writes[addr] == nil {
+		t.writes[addr] = make(map[common.Hash]common.Hash)
+	}
+	t.writes[addr][slot] = new
+}
+
+// systemSlotCheck is a storage slot of a system contract along with the value
+// it must hold after the block has been executed.
+type systemSlotCheck struct {
+	Name    string
+	Address common.Address
+	Slot    common.Hash
+	Want    common.Hash
+}
+
+// expectedSystemSlots lists the post-execution storage expectations for the
+// system calls active at the given block.
+func expectedSystemSlots(config *params.ChainConfig, header *types.Header) []systemSlotCheck {
+	var checks []systemSlotCheck
+	if config.IsCancun(header.Number, header.Time) && header.ParentBeaconRoot != nil {
+		index := header.Time % beaconRootsBufferLength
+		checks = append(checks,
+			systemSlotCheck{
+				Name:    "EIP-4788 beacon root timestamp",
+				Address: params.BeaconRootsAddress,
+				Slot:    common.BigToHash(new(big.Int).SetUint64(index)),
+				Want:    common.BigToHash(new(big.Int).SetUint64(header.Time)),
+			},
+			systemSlotCheck{
+				Name:    "EIP-4788 beacon root",
+				Address: params.BeaconRootsAddress,
+				Slot:    common.BigToHash(new(big.Int).SetUint64(index + beaconRootsBufferLength)),
+				Want:    *header.ParentBeaconRoot,
+			},
+		)
+	}
+	if config.IsPrague(header.Number, header.Time) {
+		if number := header.Number.Uint64(); number > 0 {
+			checks = append(checks, systemSlotCheck{
+				Name:    "EIP-2935 parent block hash",
+				Address: params.HistoryStorageAddress,
+				Slot:    common.BigToHash(new(big.Int).SetUint64((number - 1) % params.HistoryServeWindow)),
+				Want:    header.ParentHash,
+			})
+		}
+		checks = append(checks,
+			systemSlotCheck{
+				Name:    "EIP-7002 withdrawal request count",
+				Address: params.WithdrawalQueueAddress,
+				Slot:    requestCountSlot,
+			},
+			systemSlotCheck{
+				Name:    "EIP-7251 consolidation request count",
+				Address: params.ConsolidationQueueAddress,
+				Slot:    requestCountSlot,
+			},
+		)
+	}
+	return checks
+}
+
+// verifySystemCalls checks that the system contracts ended up in the state the
+// system calls of the block should have left them in. Slots not written during
+// execution are read from the witness pre-state.
+func verifySystemCalls(config *params.ChainConfig, header *types.Header, witness *stateless.Witness, tracer *systemCallTracer) error {
+	var state *witnessState
+	for _, check := range expectedSystemSlots(config, header) {
+		have, written := tracer.writes[check.Address][check.Slot]
+		if !written {
+			if state == nil {
+				var err error
+				if state, err = newWitnessState(witness); err != nil {
+					return err
+				}
+			}
+			value, err := state.storage(check.Address, check.Slot)
+			if err != nil {
+				return fmt.Errorf("%s: failed to read slot %s of %s: %v", check.Name, check.Slot.Hex(), check.Address.Hex(), err)
+			}
+			have = common.BytesToHash(value)
+		}
+		if have != check.Want {
+			return fmt.Errorf("%s mismatch at slot %s of %s (have %s, want %s)", check.Name, check.Slot.Hex(), check.Address.Hex(), have.Hex(), check.Want.Hex())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestVerifySystemCalls tests that system contract storage is checked against
+// the expectations of the active forks.
+func TestVerifySystemCalls(t *testing.T) {
+	beaconRoot := common.HexToHash("0xbeac")
+	header := &types.Header{
+		Number:           big.NewInt(22_500_000),
+		Time:             *params.MainnetChainConfig.PragueTime,
+		ParentHash:       common.HexToHash("0x9a7e"),
+		ParentBeaconRoot: &beaconRoot,
+	}
+	// The request counts are never written, so they are read from an empty
+	// pre-state and must come out as zero.
+	witness := &stateless.Witness{Headers: []*types.Header{{Number: big.NewInt(22_499_999), Root: types.EmptyRootHash}}}
+
+	checks := expectedSystemSlots(params.MainnetChainConfig, header)
+	if len(checks) != 5 {
+		t.Fatalf("expected 5 system slot checks, got %d", len(checks))
+	}
+	tracer := newSystemCallTracer()
+	for _, check := range checks {
+		if check.Want != (common.Hash{}) {
+			tracer.onStorageChange(check.Address, check.Slot, common.Hash{}, check.Want)
+		}
+	}
+	if err := verifySystemCalls(params.MainnetChainConfig, header, witness, tracer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A history contract that stored the wrong parent hash must be caught.
+	tracer.onStorageChange(params.HistoryStorageAddress, checks[2].Slot, checks[2].Want, common.HexToHash("0xbad"))
+	err := verifySystemCalls(params.MainnetChainConfig, header, witness, tracer)
+	if err == nil || !strings.Contains(err.Error(), "EIP-2935") {
+		t.Fatalf("expected EIP-2935 mismatch, got %v", err)
+	}
+
+	// Before Cancun there is nothing to check.
+	header = &types.Header{Number: big.NewInt(100), Time: 0}
+	if checks := expectedSystemSlots(params.MainnetChainConfig, header); len(checks) != 0 {
+		t.Fatalf("expected no checks before Cancun, got %d", len(checks))
+	}
+}
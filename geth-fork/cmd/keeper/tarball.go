@@ -0,0 +1,76 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// readTarPayloads reads the payloads from the regular files of a gzipped tar
+// archive, in archive order, returning the member names along with their
+// decoded contents. Each member is decoded from the given input format and
+// must not exceed the maximum input size.
+func readTarPayloads(path string, format string) ([]string, [][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+
+	var (
+		names    []string
+		payloads [][]byte
+		archive  = tar.NewReader(gz)
+	)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if limit := maxEncodedInputSize(); header.Size > limit {
+			return nil, nil, fmt.Errorf("member %s exceeds maximum encoded size (%d > %d)", header.Name, header.Size, limit)
+		}
+		raw, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, nil, fmt.Errorf("member %s: %v", header.Name, err)
+		}
+		payload, _, err := decodeInput(raw, format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("member %s: %v", header.Name, err)
+		}
+		names = append(names, header.Name)
+		payloads = append(payloads, payload)
+	}
+	return names, payloads, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestTarPayloads tests that every regular file of a gzipped tar archive is
+// validated and reported by its member name.
+func TestTarPayloads(t *testing.T) {
+	valid := process(makeEmptyPayload(t, common.Hash{}, types.EmptyReceiptsHash))
+	members := []struct {
+		name string
+		data []byte
+	}{
+		{"blocks/1.rlp", makeEmptyPayload(t, valid.StateRoot, types.EmptyReceiptsHash)},
+		{"blocks/2.rlp", []byte{0x01}},
+	}
+	path := filepath.Join(t.TempDir(), "payloads.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	archive := tar.NewWriter(gz)
+	if err := archive.WriteHeader(&tar.Header{Name: "blocks/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatal(err)
+	}
+	for _, member := range members {
+		if err := archive.WriteHeader(&tar.Header{Name: member.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(member.data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write(member.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive.Close()
+	gz.Close()
+	f.Close()
+
+	names, payloads, err := readTarPayloads(path, formatRaw)
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+	if len(names) != 2 || names[0] != "blocks/1.rlp" || names[1] != "blocks/2.rlp" {
+		t.Fatalf("unexpected members %v", names)
+	}
+	var results []*Result
+	config := &batchConfig{print: func(result *Result) error {
+		results = append(results, result)
+		return nil
+	}}
+	if code := runPayloads(payloads, names, config); code != ExitBatchFailed {
+		t.Errorf("exit code = %d, want %d", code, ExitBatchFailed)
+	}
+	if len(results) != 2 || results[0].Member != "blocks/1.rlp" || !results[0].Valid || results[1].Member != "blocks/2.rlp" || results[1].Valid {
+		t.Errorf("unexpected results %+v", results)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errExecutionTimeout is returned by runWithDeadline if execution did not
+// complete before the deadline.
+var errExecutionTimeout = errors.New("execution timed out")
+
+// runWithDeadline runs the given block execution, returning its state and
+// receipt roots, but gives up on it once the deadline passes. Execution cannot
+// be interrupted, so it is then abandoned: it keeps running in the background,
+// holding on to its memory and a CPU, until it completes on its own. This is
+// harmless for a single payload, after which the process exits, but in batch
+// mode every abandoned execution keeps consuming resources while the next
+// payloads are validated. A zero deadline runs the execution unbounded. Panics
+// of the execution are recovered and returned as an executionPanic error.
+func runWithDeadline(deadline time.Time, execute func() (common.Hash, common.Hash, error)) (common.Hash, common.Hash, error) {
+	if deadline.IsZero() {
+		return recoverExecution(execute)
+	}
+	type outcome struct {
+		stateRoot, receiptRoot common.Hash
+		err                    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		stateRoot, receiptRoot, err := recoverExecution(execute)
+		done <- outcome{stateRoot, receiptRoot, err}
+	}()
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case out := <-done:
+		return out.stateRoot, out.receiptRoot, out.err
+	case <-timer.C:
+		return common.Hash{}, common.Hash{}, errExecutionTimeout
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestRunWithDeadline tests that executions completing in time are returned as
+// is, and that late ones are abandoned once the deadline passes.
+func TestRunWithDeadline(t *testing.T) {
+	fast := func() (common.Hash, common.Hash, error) {
+		return common.Hash{0x01}, common.Hash{0x02}, nil
+	}
+	for _, deadline := range []time.Time{{}, time.Now().Add(time.Minute)} {
+		stateRoot, receiptRoot, err := runWithDeadline(deadline, fast)
+		if err != nil || stateRoot != (common.Hash{0x01}) || receiptRoot != (common.Hash{0x02}) {
+			t.Errorf("deadline %v: got %x %x %v", deadline, stateRoot, receiptRoot, err)
+		}
+	}
+	release := make(chan struct{})
+	defer close(release)
+	slow := func() (common.Hash, common.Hash, error) {
+		<-release
+		return common.Hash{0x01}, common.Hash{0x02}, nil
+	}
+	start := time.Now()
+	if _, _, err := runWithDeadline(start.Add(10*time.Millisecond), slow); !errors.Is(err, errExecutionTimeout) {
+		t.Fatalf("error %v, want %v", err, errExecutionTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("execution abandoned after %v", elapsed)
+	}
+}
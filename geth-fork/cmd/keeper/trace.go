@@ -0,0 +1,52 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	evmlogger "github.com/ethereum/go-ethereum/eth/tracers/logger"
+)
+
+// txTraceHeader introduces the trace of a transaction in a trace file.
+type txTraceHeader struct {
+	TxIndex int         `json:"txIndex"`
+	TxHash  common.Hash `json:"txHash"`
+}
+
+// newTraceHooks returns hooks writing the execution trace of every transaction
+// of the block to w, as a stream of JSON lines: one identifying the transaction,
+// followed by one per executed opcode with the stack, and one per returning
+// call frame, as written by the JSON logger of evm t8n. System calls are not
+// traced.
+func newTraceHooks(w io.Writer) *tracing.Hooks {
+	var (
+		encoder = json.NewEncoder(w)
+		index   int
+	)
+	header := &tracing.Hooks{
+		OnTxStart: func(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+			encoder.Encode(txTraceHeader{TxIndex: index, TxHash: tx.Hash()})
+			index++
+		},
+	}
+	return joinHooks(header, evmlogger.NewJSONLogger(nil, w))
+}
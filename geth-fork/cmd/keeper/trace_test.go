@@ -0,0 +1,66 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestTraceHooks tests that every transaction trace is introduced by a line
+// identifying the transaction, and that system calls are not traced.
+func TestTraceHooks(t *testing.T) {
+	var buf bytes.Buffer
+	hooks := newTraceHooks(&buf)
+
+	txs := []*types.Transaction{types.NewTx(&types.LegacyTx{Nonce: 0}), types.NewTx(&types.LegacyTx{Nonce: 1})}
+	hooks.OnSystemCallStart()
+	hooks.OnExit(0, []byte{0x01}, 0, nil, false)
+	hooks.OnSystemCallEnd()
+	for _, tx := range txs {
+		hooks.OnTxStart(nil, tx, common.Address{})
+		hooks.OnExit(0, nil, 21000, nil, false)
+		hooks.OnTxEnd(&types.Receipt{}, nil)
+	}
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid trace line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("trace holds %d lines, want 4: %v", len(lines), lines)
+	}
+	for i, tx := range txs {
+		header, result := lines[2*i], lines[2*i+1]
+		if header["txIndex"] != float64(i) || header["txHash"] != tx.Hash().Hex() {
+			t.Errorf("transaction %d introduced by %v", i, header)
+		}
+		if result["gasUsed"] != "0x5208" {
+			t.Errorf("transaction %d ended with %v", i, result)
+		}
+	}
+}
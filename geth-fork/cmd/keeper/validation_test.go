@@ -17,6 +17,7 @@
 package main
 
 import (
+        "math/big"
         "strings"
         "testing"
 
@@ -176,6 +177,63 @@ func TestValidatePayload(t *testing.T) {
         }
 }
 
+// TestValidateWitnessSize tests the witness size limit
+func TestValidateWitnessSize(t *testing.T) {
+        witness := &stateless.Witness{
+                Codes: map[string]struct{}{string(make([]byte, 100)): {}},
+                State: map[string]struct{}{string(make([]byte, 50)): {}},
+        }
+        if size := witnessSize(witness); size != 150 {
+                t.Fatalf("witnessSize() = %d, want 150", size)
+        }
+        tests := []struct {
+                name    string
+                limit   uint64
+                wantErr bool
+        }{
+                {name: "unlimited", limit: 0, wantErr: false},
+                {name: "under limit", limit: 200, wantErr: false},
+                {name: "at limit", limit: 150, wantErr: false},
+                {name: "over limit", limit: 149, wantErr: true},
+        }
+        for _, tt := range tests {
+                t.Run(tt.name, func(t *testing.T) {
+                        err := validateWitnessSize(witness, tt.limit)
+                        if (err != nil) != tt.wantErr {
+                                t.Errorf("validateWitnessSize() error = %v, wantErr %v", err, tt.wantErr)
+                        }
+                        if err != nil && !strings.Contains(err.Error(), "exceeds maximum size") {
+                                t.Errorf("unexpected error: %v", err)
+                        }
+                })
+        }
+}
+
+// TestValidateWitnessNodes tests the witness trie node count limit
+func TestValidateWitnessNodes(t *testing.T) {
+        witness := &stateless.Witness{
+                Codes: map[string]struct{}{"code": {}},
+                State: map[string]struct{}{"a": {}, "b": {}, "c": {}},
+        }
+        tests := []struct {
+                limit   uint64
+                wantErr bool
+        }{
+                {limit: 0, wantErr: false},
+                {limit: 3, wantErr: false},
+                {limit: 2, wantErr: true},
+        }
+        for _, tt := range tests {
+                err := validateWitnessNodes(witness, tt.limit)
+                if (err != nil) != tt.wantErr {
+                        t.Errorf("limit %d: validateWitnessNodes() error = %v, wantErr %v", tt.limit, err, tt.wantErr)
+                }
+                if err != nil && !strings.Contains(err.Error(), "too many trie nodes (3 > 2)") {
+                        t.Errorf("unexpected error: %v", err)
+                }
+        }
+}
+
 // TestMaxInputSize verifies the constant is set correctly
 func TestMaxInputSize(t *testing.T) {
         expected := 100 * 1024 * 1024 // 100 MB
@@ -184,6 +242,33 @@ func TestMaxInputSize(t *testing.T) {
         }
 }
 
+// TestValidateTxCount tests the transaction count limit
+func TestValidateTxCount(t *testing.T) {
+        txs := make(types.Transactions, 3)
+        for i := range txs {
+                txs[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i)})
+        }
+        block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(types.Body{Transactions: txs})
+
+        tests := []struct {
+                name    string
+                limit   uint64
+                wantErr bool
+        }{
+                {name: "unlimited", limit: 0, wantErr: false},
+                {name: "at limit", limit: 3, wantErr: false},
+                {name: "over limit", limit: 2, wantErr: true},
+        }
+        for _, tt := range tests {
+                t.Run(tt.name, func(t *testing.T) {
+                        err := validateTxCount(block, tt.limit)
+                        if (err != nil) != tt.wantErr {
+                                t.Errorf("validateTxCount() error = %v, wantErr %v", err, tt.wantErr)
+                        }
+                })
+        }
+}
+
 // TestExitCodes verifies exit code constants are unique
 func TestExitCodes(t *testing.T) {
         codes := map[int]string{
@@ -195,10 +280,28 @@ func TestExitCodes(t *testing.T) {
                 ExitInvalidInput:       "ExitInvalidInput",
                 ExitDecodeFailed:       "ExitDecodeFailed",
                 ExitValidationFailed:   "ExitValidationFailed",
+                ExitWitnessInvalid:     "ExitWitnessInvalid",
+                ExitSystemCallMismatch: "ExitSystemCallMismatch",
+                ExitBatchFailed:        "ExitBatchFailed",
+                ExitTooManyTxs:         "ExitTooManyTxs",
+                ExitTrailingBytes:      "ExitTrailingBytes",
+                ExitHeaderInconsistent: "ExitHeaderInconsistent",
+                ExitForkNotActivated:   "ExitForkNotActivated",
+                ExitChainConfigMismatch: "ExitChainConfigMismatch",
+                ExitMemoryExceeded:     "ExitMemoryExceeded",
+                ExitWithdrawalsMismatch: "ExitWithdrawalsMismatch",
+                ExitWitnessIncomplete:  "ExitWitnessIncomplete",
+                ExitTimeout:            "ExitTimeout",
+                ExitReceiptCountMismatch: "ExitReceiptCountMismatch",
+                ExitBlobGasMismatch:    "ExitBlobGasMismatch",
+                ExitResourceExhausted:  "ExitResourceExhausted",
+                ExitWitnessTooLarge:    "ExitWitnessTooLarge",
+                ExitInterrupted:        "ExitInterrupted",
+                ExitBlockHashMismatch:  "ExitBlockHashMismatch",
         }
 
         // Check all expected codes are present
-        expectedCount := 8
+        expectedCount := 26
         if len(codes) != expectedCount {
                 t.Errorf("expected %d unique exit codes, got %d", expectedCount, len(codes))
         }
@@ -216,6 +319,24 @@ func TestExitCodes(t *testing.T) {
         }
 }
 
+// FuzzValidateInput checks that input validation never panics and only accepts
+// non-empty RLP lists within the size limit.
+func FuzzValidateInput(f *testing.F) {
+        f.Add([]byte{})
+        f.Add([]byte{0x80})
+        f.Add([]byte{0xc0})
+        f.Add([]byte{0xf9, 0x01, 0x00})
+        f.Add(append([]byte{0xf8, 0xff}, make([]byte, 255)...))
+
+        f.Fuzz(func(t *testing.T, input []byte) {
+                if err := validateInput(input); err == nil {
+                        if len(input) == 0 || len(input) > MaxInputSize || input[0] < 0xc0 {
+                                t.Fatalf("accepted invalid input %x", input)
+                        }
+                }
+        })
+}
+
 // BenchmarkValidateInput benchmarks the input validation
 func BenchmarkValidateInput(b *testing.B) {
         // Create a valid RLP list input
@@ -0,0 +1,46 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// verifyWithdrawalsRoot recomputes the withdrawals trie root from the
+// withdrawals list carried by the block and compares it with the root declared
+// in the header. Execution credits the withdrawals of the list, while the block
+// hash only commits to the header root, so a tampered list would otherwise go
+// unnoticed as long as the state root is adjusted accordingly. Blocks without a
+// withdrawals list are not checked.
+func verifyWithdrawalsRoot(block *types.Block) error {
+	withdrawals := block.Withdrawals()
+	if withdrawals == nil {
+		return nil
+	}
+	declared := block.Header().WithdrawalsHash
+	if declared == nil {
+		return errors.New("block carries withdrawals but the header has no withdrawals root")
+	}
+	if root := types.DeriveSha(withdrawals, trie.NewStackTrie(nil)); root != *declared {
+		return fmt.Errorf("withdrawals root mismatch (header: %x computed: %x from %d withdrawals)", *declared, root, len(withdrawals))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// TestVerifyWithdrawalsRoot tests that withdrawals lists are checked against the
+// withdrawals root of the header.
+func TestVerifyWithdrawalsRoot(t *testing.T) {
+	withdrawals := types.Withdrawals{
+		{Index: 1, Validator: 10, Address: common.Address{0x01}, Amount: 32},
+		{Index: 2, Validator: 11, Address: common.Address{0x02}, Amount: 64},
+	}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	block := types.NewBlock(header, &types.Body{Withdrawals: withdrawals}, nil, trie.NewStackTrie(nil))
+
+	tampered := types.Withdrawals{withdrawals[0], {Index: 2, Validator: 11, Address: common.Address{0x03}, Amount: 64}}
+	tests := []struct {
+		block *types.Block
+		want  string
+	}{
+		{block, ""},
+		{types.NewBlock(header, &types.Body{Withdrawals: types.Withdrawals{}}, nil, trie.NewStackTrie(nil)), ""},
+		{types.NewBlockWithHeader(header), ""},
+		{block.WithBody(types.Body{Withdrawals: tampered}), "withdrawals root mismatch"},
+		{block.WithBody(types.Body{Withdrawals: withdrawals[:1]}), "withdrawals root mismatch"},
+		{types.NewBlockWithHeader(header).WithBody(types.Body{Withdrawals: withdrawals}), "no withdrawals root"},
+	}
+	for i, tt := range tests {
+		err := verifyWithdrawalsRoot(tt.block)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("test %d: error %v, want %q", i, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// witnessSize returns the number of bytes held by the witness: the encoded
+// ancestor headers plus every bytecode and trie node blob it carries.
+func witnessSize(witness *stateless.Witness) uint64 {
+	var size uint64
+	for _, header := range witness.Headers {
+		if header == nil {
+			continue
+		}
+		enc, err := rlp.EncodeToBytes(header)
+		if err != nil {
+			continue
+		}
+		size += uint64(len(enc))
+	}
+	for code := range witness.Codes {
+		size += uint64(len(code))
+	}
+	for node := range witness.State {
+		size += uint64(len(node))
+	}
+	return size
+}
+
+// validateWitnessSize checks the decoded witness against the configured size
+// limit. An abnormally large witness for a block is a sign of a generation bug
+// or an attack, even when the payload as a whole fits in the maximum input
+// size. A limit of zero disables the check.
+func validateWitnessSize(witness *stateless.Witness, limit uint64) error {
+	if limit == 0 {
+		return nil
+	}
+	if size := witnessSize(witness); size > limit {
+		return fmt.Errorf("witness exceeds maximum size (%d > %d)", size, limit)
+	}
+	return nil
+}
+
+// validateWitnessNodes checks the number of trie nodes of the decoded witness
+// against the configured limit. Every node is a separate allocation and map
+// entry during execution, so a witness inflated with many tiny nodes costs
+// far more than its size suggests. A limit of zero disables the check.
+func validateWitnessNodes(witness *stateless.Witness, limit uint64) error {
+	if limit == 0 {
+		return nil
+	}
+	if count := uint64(len(witness.State)); count > limit {
+		return fmt.Errorf("witness has too many trie nodes (%d > %d)", count, limit)
+	}
+	return nil
+}
+
+// verifyWitnessComplete checks that the witness can serve stateless execution
+// of the block before it is attempted: its headers must form a chain of
+// ancestors starting at the parent of the block, and it must carry the root
+// node of the parent state trie. Execution would otherwise fail deep inside
+// the EVM with an opaque missing trie node error.
+func verifyWitnessComplete(block *types.Block, witness *stateless.Witness) error {
+	if err := verifyWitnessHeaders(block, witness); err != nil {
+		return err
+	}
+	root := witness.Root()
+	if root == types.EmptyRootHash {
+		return nil
+	}
+	for node := range witness.State {
+		if crypto.Keccak256Hash([]byte(node)) == root {
+			return nil
+		}
+	}
+	return fmt.Errorf("witness is missing the parent state root node %x", root)
+}
+
+// verifyWitnessHeaders checks that the headers of the witness form a chain of
+// ancestors starting at the parent of the block.
+func verifyWitnessHeaders(block *types.Block, witness *stateless.Witness) error {
+	if len(witness.Headers) == 0 || witness.Headers[0] == nil {
+		return errors.New("witness has no parent header")
+	}
+	if hash := witness.Headers[0].Hash(); hash != block.ParentHash() {
+		return fmt.Errorf("witness parent header %x does not match block parent hash %x", hash, block.ParentHash())
+	}
+	for i := 1; i < len(witness.Headers); i++ {
+		header, child := witness.Headers[i], witness.Headers[i-1]
+		if header == nil {
+			return fmt.Errorf("witness header %d is missing", i)
+		}
+		if header.Hash() != child.ParentHash {
+			return fmt.Errorf("witness header %d (block %v) is not the parent of header %d (block %v)", i, header.Number, i-1, child.Number)
+		}
+	}
+	return nil
+}
+
+// witnessParent returns the parent header of the block carried by the witness,
+// or nil if the witness holds no headers.
+func witnessParent(witness *stateless.Witness) *types.Header {
+	if len(witness.Headers) == 0 {
+		return nil
+	}
+	return witness.Headers[0]
+}
+
+// witnessState provides read access to the pre-state tries carried by a
+// witness. Lookups of accounts or slots whose trie paths are not covered by
+// the witness fail with a missing trie node error.
+type witnessState struct {
+	root common.Hash
+	db   *triedb.Database
+}
+
+// newWitnessState opens the pre-state of the witness, rooted at the state root
+// of its parent header.
+func newWitnessState(witness *stateless.Witness) (*witnessState, error) {
+	if len(witness.Headers) == 0 || witness.Headers[0] == nil {
+		return nil, errors.New("witness has no parent header")
+	}
+	return &witnessState{
+		root: witness.Root(),
+		db:   triedb.NewDatabase(witness.MakeHashDB(), triedb.HashDefaults),
+	}, nil
+}
+
+// account retrieves an account from the witness. A nil account with a nil
+// error means the witness proves the account does not exist.
+func (s *witnessState) account(addr common.Address) (*types.StateAccount, error) {
+	tr, err := trie.NewStateTrie(trie.StateTrieID(s.root), s.db)
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetAccount(addr)
+}
+
+// storage retrieves a storage slot of an account from the witness.
+func (s *witnessState) storage(addr common.Address, slot common.Hash) ([]byte, error) {
+	acc, err := s.account(addr)
+	if err != nil || acc == nil {
+		return nil, err
+	}
+	tr, err := trie.NewStateTrie(trie.StorageTrieID(s.root, crypto.Keccak256Hash(addr.Bytes()), acc.Root), s.db)
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetStorage(addr, slot.Bytes())
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestVerifyWitnessComplete tests the checks of the witness headers and of the
+// parent state root node done ahead of execution.
+func TestVerifyWitnessComplete(t *testing.T) {
+	node := []byte{0xc2, 0x80, 0x80}
+	grandparent := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1)}
+	makeWitness := func(root common.Hash, state ...[]byte) (*types.Block, *stateless.Witness) {
+		parent := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), ParentHash: grandparent.Hash(), Root: root}
+		header := &types.Header{Number: big.NewInt(2), Difficulty: big.NewInt(1), ParentHash: parent.Hash()}
+		witness, _ := stateless.NewWitness(header, nil)
+		witness.Headers = []*types.Header{parent, grandparent}
+		for _, blob := range state {
+			witness.State[string(blob)] = struct{}{}
+		}
+		return types.NewBlockWithHeader(header), witness
+	}
+	tests := []struct {
+		mutate func(*types.Block, *stateless.Witness) (*types.Block, *stateless.Witness)
+		root   common.Hash
+		state  [][]byte
+		want   string
+	}{
+		{nil, types.EmptyRootHash, nil, ""},
+		{nil, crypto.Keccak256Hash(node), [][]byte{{0x80}, node}, ""},
+		{nil, crypto.Keccak256Hash(node), [][]byte{{0x80}}, "missing the parent state root node " + crypto.Keccak256Hash(node).Hex()[2:]},
+		{func(b *types.Block, w *stateless.Witness) (*types.Block, *stateless.Witness) {
+			w.Headers = nil
+			return b, w
+		}, types.EmptyRootHash, nil, "no parent header"},
+		{func(b *types.Block, w *stateless.Witness) (*types.Block, *stateless.Witness) {
+			return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: common.Hash{0x01}}), w
+		}, types.EmptyRootHash, nil, "does not match block parent hash"},
+		{func(b *types.Block, w *stateless.Witness) (*types.Block, *stateless.Witness) {
+			w.Headers[1] = &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(2)}
+			return b, w
+		}, types.EmptyRootHash, nil, "is not the parent of header 0"},
+		{func(b *types.Block, w *stateless.Witness) (*types.Block, *stateless.Witness) {
+			w.Headers[1] = nil
+			return b, w
+		}, types.EmptyRootHash, nil, "header 1 is missing"},
+	}
+	for i, tt := range tests {
+		block, witness := makeWitness(tt.root, tt.state...)
+		if tt.mutate != nil {
+			block, witness = tt.mutate(block, witness)
+		}
+		err := verifyWitnessComplete(block, witness)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("test %d: error %v, want %q", i, err, tt.want)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
@@ -55,6 +56,20 @@ func ExecuteStateless(config *params.ChainConfig, vmconfig vm.Config, block *typ
 	if err != nil {
 		return common.Hash{}, common.Hash{}, err
 	}
+	return executeWithState(config, vmconfig, block, memdb, db)
+}
+
+// ExecuteStateful runs the block on top of a fully populated pre-state instead
+// of the one carried by the witness, and returns the state root and receipt
+// root like ExecuteStateless does. The witness is only used to serve ancestor
+// headers. Comparing the results of both methods cross-checks the witness.
+func ExecuteStateful(config *params.ChainConfig, vmconfig vm.Config, block *types.Block, witness *stateless.Witness, db *state.StateDB) (common.Hash, common.Hash, error) {
+	return executeWithState(config, vmconfig, block, witness.MakeHashDB(), db)
+}
+
+// executeWithState processes the block on top of the given state, with ancestor
+// headers served from the given database, and self-validates the result.
+func executeWithState(config *params.ChainConfig, vmconfig vm.Config, block *types.Block, memdb ethdb.Database, db *state.StateDB) (common.Hash, common.Hash, error) {
 	// Create a blockchain that is idle, but can be used to access headers through
 	chain := &HeaderChain{
 		config:      config,
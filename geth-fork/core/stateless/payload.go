@@ -0,0 +1,61 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Payload is the input of a stateless block validation: a block together with
+// the witness needed to execute it, and the chain the block belongs to.
+type Payload struct {
+	ChainID uint64
+	Block   *types.Block
+	Witness *Witness
+}
+
+// extPayload is the RLP encoding of a payload, with the witness in its
+// consensus form.
+type extPayload struct {
+	ChainID uint64
+	Block   *types.Block
+	Witness *ExtWitness
+}
+
+// EncodePayload serializes a payload as RLP. The codes and trie nodes of the
+// witness are sorted, so that the same payload always encodes to the same bytes
+// regardless of the order in which the witness was recorded.
+func EncodePayload(chainID uint64, block *types.Block, witness *Witness) ([]byte, error) {
+	ext := witness.ToExtWitness()
+	slices.SortFunc(ext.Codes, func(a, b hexutil.Bytes) int { return bytes.Compare(a, b) })
+	slices.SortFunc(ext.State, func(a, b hexutil.Bytes) int { return bytes.Compare(a, b) })
+	return rlp.EncodeToBytes(&extPayload{ChainID: chainID, Block: block, Witness: ext})
+}
+
+// DecodePayload decodes a payload serialized by EncodePayload.
+func DecodePayload(input []byte) (*Payload, error) {
+	payload := new(Payload)
+	if err := rlp.DecodeBytes(input, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
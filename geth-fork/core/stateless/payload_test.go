@@ -0,0 +1,64 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package stateless
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TestPayloadRoundTrip tests that payloads survive an encoding round trip and
+// that equal payloads always encode to the same bytes.
+func TestPayloadRoundTrip(t *testing.T) {
+	parent := &types.Header{Number: big.NewInt(41), Difficulty: big.NewInt(0)}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(42), ParentHash: parent.Hash(), Difficulty: big.NewInt(0)})
+	witness := &Witness{
+		Headers: []*types.Header{parent},
+		Codes:   map[string]struct{}{"\x60\x00": {}, "\x60\x01": {}, "\x60\x02": {}},
+		State:   map[string]struct{}{"node1": {}, "node2": {}, "node3": {}, "node4": {}},
+	}
+	enc, err := EncodePayload(1, block, witness)
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := EncodePayload(1, block, witness)
+		if err != nil || !bytes.Equal(again, enc) {
+			t.Fatalf("encoding is not deterministic: %x != %x (%v)", again, enc, err)
+		}
+	}
+	payload, err := DecodePayload(enc)
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.ChainID != 1 || payload.Block.Hash() != block.Hash() {
+		t.Errorf("decoded chain %d block %x, want chain 1 block %x", payload.ChainID, payload.Block.Hash(), block.Hash())
+	}
+	if len(payload.Witness.Headers) != 1 || payload.Witness.Headers[0].Hash() != parent.Hash() {
+		t.Errorf("witness headers not preserved")
+	}
+	if !reflect.DeepEqual(payload.Witness.Codes, witness.Codes) || !reflect.DeepEqual(payload.Witness.State, witness.State) {
+		t.Errorf("witness contents not preserved: codes %v, state %v", payload.Witness.Codes, payload.Witness.State)
+	}
+	if _, err := DecodePayload(enc[:len(enc)-1]); err == nil {
+		t.Error("expected error for truncated payload")
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
 )
 
 // SignatureLength indicates the byte length required to carry a signature with recovery id.
@@ -67,6 +68,13 @@ type KeccakState interface {
 	Read([]byte) (int, error)
 }
 
+// KeccakCloner is implemented by hashing states that can return an independent
+// copy of themselves, so that a common prefix can be absorbed once and then
+// finalized with different suffixes. Use CloneKeccakState to copy any state.
+type KeccakCloner interface {
+	Clone() KeccakState
+}
+
 // HashData hashes the provided data using the KeccakState and returns a 32 byte hash
 func HashData(kh KeccakState, data []byte) (h common.Hash) {
 	kh.Reset()
@@ -75,6 +83,31 @@ func HashData(kh KeccakState, data []byte) (h common.Hash) {
 	return h
 }
 
+// Keccak256Reader calculates and returns the Keccak256 hash of all data read
+// from r until EOF. The data is absorbed in chunks, so that large inputs can
+// be hashed without holding them in memory in full, except in ziren builds,
+// whose Keccak state buffers its input for the hashing system call.
+func Keccak256Reader(r io.Reader) ([]byte, error) {
+	d := NewKeccakState()
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, err
+	}
+	b := make([]byte, 32)
+	d.Read(b)
+	return b, nil
+}
+
+// Keccak512 calculates and returns the 64 byte Keccak512 hash of the input
+// data. It shares the permutation of Keccak256, but with a rate of 72 bytes
+// instead of 136, and the same legacy padding, not the one of SHA3-512.
+func Keccak512(data ...[]byte) []byte {
+	d := sha3.NewLegacyKeccak512()
+	for _, b := range data {
+		d.Write(b)
+	}
+	return d.Sum(nil)
+}
+
 // CreateAddress creates an ethereum address given the bytes and the nonce
 func CreateAddress(b common.Address, nonce uint64) common.Address {
 	data, _ := rlp.EncodeToBytes([]interface{}{b, nonce})
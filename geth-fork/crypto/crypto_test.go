@@ -21,6 +21,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
+	"io"
 	"math/big"
 	"os"
 	"reflect"
@@ -42,6 +43,24 @@ func TestKeccak256Hash(t *testing.T) {
 	checkhash(t, "Sha3-256-array", func(in []byte) []byte { h := Keccak256Hash(in); return h[:] }, msg, exp)
 }
 
+func TestKeccak512(t *testing.T) {
+	tests := []struct {
+		msg []byte
+		exp string
+	}{
+		{nil, "0eab42de4c3ceb9235fc91acffe746b29c29a8c366b7c60e4e67c466f36a4304c00fa9caf9d87976ba469bcbe06713b435f091ef2769fb160cdab33d3670680e"},
+		{[]byte("hello"), "52fa80662e64c128f8389c9ea6c73d4c02368004bf4463491900d11aaadca39d47de1b01361f207c512cfa79f0f92c3395c67ff7928e3f5ce3e3c852b392f976"},
+	}
+	for _, tt := range tests {
+		exp, _ := hex.DecodeString(tt.exp)
+		checkhash(t, "Keccak512", func(in []byte) []byte { return Keccak512(in) }, tt.msg, exp)
+	}
+	// Input split across arguments hashes as if concatenated.
+	if got := Keccak512([]byte("he"), []byte("llo")); !bytes.Equal(got, Keccak512([]byte("hello"))) {
+		t.Errorf("split input hashes to %x", got)
+	}
+}
+
 func TestKeccak256Hasher(t *testing.T) {
 	msg := []byte("abc")
 	exp, _ := hex.DecodeString("4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45")
@@ -49,6 +68,49 @@ func TestKeccak256Hasher(t *testing.T) {
 	checkhash(t, "Sha3-256-array", func(in []byte) []byte { h := HashData(hasher, in); return h[:] }, msg, exp)
 }
 
+func TestKeccak256Reader(t *testing.T) {
+	data := make([]byte, 100_000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	want := Keccak256(data)
+	for _, chunk := range []int{1, 7, 136, 4096, len(data)} {
+		got, err := Keccak256Reader(&chunkReader{data: data, chunk: chunk})
+		if err != nil {
+			t.Fatalf("chunk size %d: %v", chunk, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("chunk size %d: hash %x, want %x", chunk, got, want)
+		}
+	}
+	if got, err := Keccak256Reader(bytes.NewReader(nil)); err != nil || !bytes.Equal(got, Keccak256()) {
+		t.Errorf("empty input: hash %x, err %v", got, err)
+	}
+	if _, err := Keccak256Reader(&chunkReader{data: data, chunk: 100, err: io.ErrUnexpectedEOF}); err != io.ErrUnexpectedEOF {
+		t.Errorf("read error not returned: %v", err)
+	}
+}
+
+// chunkReader returns its data in chunks of at most the given size, followed by
+// the given error, or io.EOF if there is none.
+type chunkReader struct {
+	data  []byte
+	chunk int
+	err   error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), r.chunk)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
 func TestToECDSAErrors(t *testing.T) {
 	if _, err := HexToECDSA("0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
 		t.Fatal("HexToECDSA should've returned error")
@@ -315,6 +377,56 @@ func BenchmarkKeccak256Hash(b *testing.B) {
 	}
 }
 
+func TestAppendKeccak256(t *testing.T) {
+	prefix := []byte{0x01, 0x02}
+	buf := AppendKeccak256(prefix, []byte("hel"), []byte("lo"))
+	if !bytes.Equal(buf[:2], prefix) || !bytes.Equal(buf[2:], Keccak256([]byte("hello"))) {
+		t.Fatalf("appended hash mismatch: %x", buf)
+	}
+	dst := make([]byte, 0, 32)
+	if allocs := testing.AllocsPerRun(100, func() { AppendKeccak256(dst[:0], prefix) }); allocs != 0 {
+		t.Errorf("AppendKeccak256 allocated %v times per call with a sufficient buffer", allocs)
+	}
+}
+
+func TestKeccak256Into(t *testing.T) {
+	for _, data := range [][][]byte{nil, {[]byte("hello")}, {[]byte("hel"), []byte("lo")}, {make([]byte, 1000)}} {
+		var dst common.Hash
+		Keccak256Into((*[32]byte)(&dst), data...)
+		if !bytes.Equal(dst[:], Keccak256(data...)) {
+			t.Errorf("Keccak256Into(%x) = %x, want %x", data, dst, Keccak256(data...))
+		}
+	}
+	var dst [32]byte
+	if allocs := testing.AllocsPerRun(100, func() { Keccak256Into(&dst, dst[:]) }); allocs != 0 {
+		t.Errorf("Keccak256Into allocated %v times per call", allocs)
+	}
+}
+
+// BenchmarkKeccak256Alloc compares hashing small inputs with a fresh state and
+// result buffer per call against reusing a buffer with the pooled state.
+func BenchmarkKeccak256Alloc(b *testing.B) {
+	var input [64]byte
+	rand.Read(input[:])
+
+	b.Run("NewKeccakState", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			d := NewKeccakState()
+			d.Write(input[:])
+			result := make([]byte, 32)
+			d.Read(result)
+		}
+	})
+	b.Run("AppendKeccak256", func(b *testing.B) {
+		dst := make([]byte, 0, 32)
+		b.ReportAllocs()
+		for b.Loop() {
+			dst = AppendKeccak256(dst[:0], input[:])
+		}
+	})
+}
+
 // goos: darwin
 // goarch: arm64
 // pkg: github.com/ethereum/go-ethereum/crypto
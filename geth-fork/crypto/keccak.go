@@ -14,50 +14,195 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-//go:build !ziren
-
 package crypto
 
 import (
+	"encoding"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/crypto/sha3"
 )
 
-// NewKeccakState creates a new KeccakState
-func NewKeccakState() KeccakState {
-	return sha3.NewLegacyKeccak256().(KeccakState)
+// Names of the Keccak256 backends. The standard backend is the one of
+// golang.org/x/crypto, with an assembly permutation on amd64. The portable
+// backend is a plain Go implementation without assembly. The ziren backend
+// uses the hashing system call of the Ziren zkVM and exists in ziren builds
+// only, where it is the default.
+const (
+	KeccakStandard = "standard"
+	KeccakPortable = "portable"
+	KeccakZiren    = "ziren"
+)
+
+// keccakBackend is an implementation of Keccak256 along with a pool of its
+// hashing states.
+type keccakBackend struct {
+	name   string
+	new    func() KeccakState
+	direct bool // Hashes are computed by directKeccak256 instead of a pooled state
+	pool   sync.Pool
+}
+
+func newKeccakBackend(name string, new func() KeccakState, direct bool) *keccakBackend {
+	return &keccakBackend{
+		name:   name,
+		new:    new,
+		direct: direct,
+		pool:   sync.Pool{New: func() any { return new() }},
+	}
 }
 
-var hasherPool = sync.Pool{
-	New: func() any {
+var (
+	standardKeccak = newKeccakBackend(KeccakStandard, func() KeccakState {
 		return sha3.NewLegacyKeccak256().(KeccakState)
-	},
+	}, false)
+	portableKeccak = newKeccakBackend(KeccakPortable, newPortableKeccakState, false)
+
+	// keccakBackends are the backends available in this build, the default
+	// one first.
+	keccakBackends = append(platformKeccakBackends, standardKeccak, portableKeccak)
+
+	// currentKeccak is the backend used by NewKeccakState and the Keccak256
+	// functions.
+	currentKeccak = func() *atomic.Pointer[keccakBackend] {
+		p := new(atomic.Pointer[keccakBackend])
+		p.Store(keccakBackends[0])
+		return p
+	}()
+)
+
+// KeccakBackends returns the names of the Keccak256 backends available in this
+// build, the default one first.
+func KeccakBackends() []string {
+	names := make([]string, len(keccakBackends))
+	for i, backend := range keccakBackends {
+		names[i] = backend.name
+	}
+	return names
 }
 
-// Keccak256 calculates and returns the Keccak256 hash of the input data.
-func Keccak256(data ...[]byte) []byte {
-	b := make([]byte, 32)
-	d := hasherPool.Get().(KeccakState)
+// KeccakBackend returns the name of the Keccak256 backend in use.
+func KeccakBackend() string {
+	return currentKeccak.Load().name
+}
+
+// SetKeccakBackend selects the Keccak256 backend used from now on by
+// NewKeccakState and the Keccak256 functions of this package. All backends
+// compute the same hashes, they only differ in speed, which depends on the
+// platform. Hashing states created before the switch keep their backend.
+func SetKeccakBackend(name string) error {
+	i := slices.IndexFunc(keccakBackends, func(backend *keccakBackend) bool {
+		return backend.name == name
+	})
+	if i < 0 {
+		return fmt.Errorf("unknown Keccak backend %q, available: %s", name, strings.Join(KeccakBackends(), ", "))
+	}
+	currentKeccak.Store(keccakBackends[i])
+	return nil
+}
+
+// sha3State is the hashing state of golang.org/x/crypto/sha3, which exposes its
+// sponge through binary marshaling only.
+type sha3State interface {
+	KeccakState
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// CloneKeccakState returns an independent copy of a hashing state. States of
+// the backends of this package and legacy Keccak256 states of x/crypto/sha3
+// can be cloned, others yield an error.
+func CloneKeccakState(state KeccakState) (KeccakState, error) {
+	switch s := state.(type) {
+	case KeccakCloner:
+		return s.Clone(), nil
+	case *countingKeccakState:
+		inner, err := CloneKeccakState(s.KeccakState)
+		if err != nil {
+			return nil, err
+		}
+		return &countingKeccakState{KeccakState: inner, read: s.read}, nil
+	case sha3State:
+		// The sponge is restored into a fresh legacy Keccak256 state, which
+		// rejects the sponges of other sha3 variants.
+		blob, err := s.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("keccak: failed to marshal state: %w", err)
+		}
+		clone := sha3.NewLegacyKeccak256().(sha3State)
+		if err := clone.UnmarshalBinary(blob); err != nil {
+			return nil, fmt.Errorf("keccak: failed to clone state: %w", err)
+		}
+		return clone, nil
+	}
+	return nil, fmt.Errorf("keccak: cannot clone state of type %T", state)
+}
+
+// NewKeccakState creates a new KeccakState
+func NewKeccakState() KeccakState {
+	state := currentKeccak.Load().new()
+	if keccakCounting.Load() {
+		return &countingKeccakState{KeccakState: state}
+	}
+	return state
+}
+
+// keccak256 writes the Keccak256 hash of the input data into the first 32
+// bytes of dst, using the current backend.
+func keccak256(dst []byte, data [][]byte) {
+	if keccakCounting.Load() {
+		var size int
+		for _, b := range data {
+			size += len(b)
+		}
+		countKeccak(1, size)
+	}
+	backend := currentKeccak.Load()
+	if backend.direct {
+		directKeccak256(dst, data)
+		return
+	}
+	d := backend.pool.Get().(KeccakState)
 	d.Reset()
 	for _, b := range data {
 		d.Write(b)
 	}
-	d.Read(b)
-	hasherPool.Put(d)
+	d.Read(dst[:32])
+	backend.pool.Put(d)
+}
+
+// Keccak256 calculates and returns the Keccak256 hash of the input data.
+func Keccak256(data ...[]byte) []byte {
+	b := make([]byte, 32)
+	keccak256(b, data)
 	return b
 }
 
+// AppendKeccak256 appends the Keccak256 hash of the input data to dst and
+// returns the extended buffer. Hashing state is drawn from a pool, so when dst
+// has room for the hash, no memory is allocated, allowing callers hashing many
+// small inputs to reuse one buffer across calls.
+func AppendKeccak256(dst []byte, data ...[]byte) []byte {
+	n := len(dst)
+	dst = slices.Grow(dst, 32)[:n+32]
+	keccak256(dst[n:], data)
+	return dst
+}
+
+// Keccak256Into calculates the Keccak256 hash of the input data and writes it
+// into dst, such as a hash field of a struct, without allocating.
+func Keccak256Into(dst *[32]byte, data ...[]byte) {
+	keccak256(dst[:], data)
+}
+
 // Keccak256Hash calculates and returns the Keccak256 hash of the input data,
 // converting it to an internal Hash data structure.
 func Keccak256Hash(data ...[]byte) (h common.Hash) {
-	d := hasherPool.Get().(KeccakState)
-	d.Reset()
-	for _, b := range data {
-		d.Write(b)
-	}
-	d.Read(h[:])
-	hasherPool.Put(d)
+	keccak256(h[:], data)
 	return h
 }
@@ -0,0 +1,144 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// withKeccakBackend runs fn with the named Keccak backend selected, restoring
+// the previous backend afterwards.
+func withKeccakBackend(t testing.TB, name string, fn func()) {
+	defer SetKeccakBackend(KeccakBackend())
+	if err := SetKeccakBackend(name); err != nil {
+		t.Fatal(err)
+	}
+	fn()
+}
+
+// TestKeccakBackends runs the Keccak256 test vectors against every backend
+// available in this build.
+func TestKeccakBackends(t *testing.T) {
+	if err := SetKeccakBackend("sha3-avx512"); err == nil {
+		t.Error("unknown backend accepted")
+	}
+	for _, name := range KeccakBackends() {
+		t.Run(name, func(t *testing.T) {
+			withKeccakBackend(t, name, func() {
+				if backend := KeccakBackend(); backend != name {
+					t.Fatalf("backend in use %q, want %q", backend, name)
+				}
+				TestKeccak256StandardVectors(t)
+				TestKeccak256KnownVectors(t)
+				TestKeccak256MultipleChunks(t)
+				TestKeccakState(t)
+				TestKeccakStateReset(t)
+				TestKeccakStateClone(t)
+				TestKeccakStateSize(t)
+				TestKeccak256HashVariant(t)
+				TestKeccak256Reader(t)
+				TestAppendKeccak256(t)
+				TestKeccak256Into(t)
+			})
+		})
+	}
+}
+
+// TestPortableKeccakState tests the portable sponge against the standard one
+// for inputs around the rate, written in chunks, and for outputs longer than
+// a block.
+func TestPortableKeccakState(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 135, 136, 137, 271, 272, 1000} {
+		data := make([]byte, size)
+		rng.Read(data)
+
+		standard, portable := standardKeccak.new(), newPortableKeccakState()
+		for rest := data; len(rest) > 0; {
+			n := min(len(rest), 1+rng.Intn(100))
+			standard.Write(rest[:n])
+			portable.Write(rest[:n])
+			rest = rest[n:]
+		}
+		if want, have := standard.Sum(nil), portable.Sum(nil); !bytes.Equal(want, have) {
+			t.Errorf("size %d: sum %x, want %x", size, have, want)
+		}
+		want, have := make([]byte, 300), make([]byte, 300)
+		standard.Read(want[:7])
+		standard.Read(want[7:])
+		portable.Read(have[:7])
+		portable.Read(have[7:])
+		if !bytes.Equal(want, have) {
+			t.Errorf("size %d: output %x, want %x", size, have, want)
+		}
+	}
+}
+
+// BenchmarkKeccakBackends compares the Keccak256 backends available in this
+// build on input sizes typical of trie nodes and contract code.
+func BenchmarkKeccakBackends(b *testing.B) {
+	for _, name := range KeccakBackends() {
+		for _, size := range []int{32, 532, 24576} {
+			b.Run(fmt.Sprintf("%s/%d", name, size), func(b *testing.B) {
+				input := make([]byte, size)
+				rand.Read(input)
+
+				withKeccakBackend(b, name, func() {
+					var h [32]byte
+					b.SetBytes(int64(size))
+					b.ReportAllocs()
+					for b.Loop() {
+						Keccak256Into(&h, input)
+					}
+				})
+			})
+		}
+	}
+}
+
+// TestCloneKeccakState tests cloning states that do not come from a backend:
+// legacy Keccak256 states of x/crypto/sha3, counting states, and states that
+// cannot be cloned.
+func TestCloneKeccakState(t *testing.T) {
+	want := Keccak256([]byte("hello world"))
+	for name, state := range map[string]KeccakState{
+		"sha3":     sha3.NewLegacyKeccak256().(KeccakState),
+		"counting": &countingKeccakState{KeccakState: newPortableKeccakState()},
+	} {
+		state.Write([]byte("hello "))
+		clone, err := CloneKeccakState(state)
+		if err != nil {
+			t.Fatalf("%s: failed to clone state: %v", name, err)
+		}
+		state.Write([]byte("there"))
+		clone.Write([]byte("world"))
+		if got := clone.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("%s: clone hash mismatch: got=%x, want=%x", name, got, want)
+		}
+	}
+	if _, err := CloneKeccakState(sha3.New256().(KeccakState)); err == nil {
+		t.Error("cloned a SHA3-256 state into a Keccak256 one")
+	}
+	if _, err := CloneKeccakState(struct{ KeccakState }{NewKeccakState()}); err == nil {
+		t.Error("cloned a state that does not support cloning")
+	}
+}
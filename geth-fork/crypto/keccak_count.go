@@ -0,0 +1,92 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import "sync/atomic"
+
+// KeccakCounts holds the number of Keccak256 hashes computed and the number of
+// bytes hashed while counting was enabled. Keccak256 dominates the cost of
+// proving an execution in a zkVM, so the counts estimate the proving cost.
+type KeccakCounts struct {
+	Calls uint64
+	Bytes uint64
+}
+
+var (
+	keccakCounting atomic.Bool
+	keccakCalls    atomic.Uint64
+	keccakBytes    atomic.Uint64
+)
+
+// SetKeccakCounting enables or disables counting the Keccak256 hashes computed
+// by the functions of this package and by hashing states from NewKeccakState.
+// Only states created while counting is enabled are counted, so it has to be
+// enabled before any states are pooled, such as at the start of a program.
+func SetKeccakCounting(enabled bool) {
+	keccakCounting.Store(enabled)
+}
+
+// KeccakCount returns the Keccak256 hashes counted since the last reset.
+func KeccakCount() KeccakCounts {
+	return KeccakCounts{Calls: keccakCalls.Load(), Bytes: keccakBytes.Load()}
+}
+
+// ResetKeccakCount resets the Keccak256 counts to zero.
+func ResetKeccakCount() {
+	keccakCalls.Store(0)
+	keccakBytes.Store(0)
+}
+
+// countKeccak adds a hash of the given number of bytes to the counts, if
+// counting is enabled.
+func countKeccak(calls, bytes int) {
+	if keccakCounting.Load() {
+		keccakCalls.Add(uint64(calls))
+		keccakBytes.Add(uint64(bytes))
+	}
+}
+
+// countingKeccakState counts the hashes computed by a hashing state: the bytes
+// written to it, and a call whenever a hash is read from it or summed.
+type countingKeccakState struct {
+	KeccakState
+	read bool // Whether the hash was read since the last reset
+}
+
+func (s *countingKeccakState) Write(p []byte) (int, error) {
+	countKeccak(0, len(p))
+	return s.KeccakState.Write(p)
+}
+
+func (s *countingKeccakState) Read(p []byte) (int, error) {
+	// Squeezing more output from the same state does not hash again.
+	if !s.read {
+		s.read = true
+		countKeccak(1, 0)
+	}
+	return s.KeccakState.Read(p)
+}
+
+func (s *countingKeccakState) Sum(b []byte) []byte {
+	countKeccak(1, 0)
+	return s.KeccakState.Sum(b)
+}
+
+func (s *countingKeccakState) Reset() {
+	s.read = false
+	s.KeccakState.Reset()
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestKeccakCounting tests that hashes computed through the package functions
+// and hashing states are counted while counting is enabled.
+func TestKeccakCounting(t *testing.T) {
+	defer SetKeccakCounting(false)
+	SetKeccakCounting(true)
+	ResetKeccakCount()
+
+	Keccak256([]byte("abc"), []byte("de"))
+	Keccak256Hash(make([]byte, 200))
+	if counts := KeccakCount(); counts != (KeccakCounts{Calls: 2, Bytes: 205}) {
+		t.Fatalf("counts after Keccak256 = %+v, want 2 calls of 205 bytes", counts)
+	}
+	state := NewKeccakState()
+	state.Write(make([]byte, 10))
+	var digest [64]byte
+	state.Read(digest[:32])
+	state.Read(digest[32:])
+	if !bytes.Equal(digest[:32], Keccak256(make([]byte, 10))) {
+		t.Errorf("counting state computed a wrong hash %x", digest[:32])
+	}
+	state.Reset()
+	state.Write(make([]byte, 5))
+	state.Sum(nil)
+	if counts := KeccakCount(); counts != (KeccakCounts{Calls: 5, Bytes: 230}) {
+		t.Errorf("counts after hashing states = %+v, want 5 calls of 230 bytes", counts)
+	}
+	SetKeccakCounting(false)
+	Keccak256([]byte("abc"))
+	state.Write([]byte("abc"))
+	if counts := KeccakCount(); counts != (KeccakCounts{Calls: 5, Bytes: 230}) {
+		t.Errorf("hashes counted while disabled: %+v", counts)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// keccak256Rate is the number of bytes absorbed per permutation by Keccak256.
+const keccak256Rate = 136
+
+// keccakRoundConstants are the constants XORed into the first lane in the iota
+// step of each of the 24 rounds of Keccak-f[1600].
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations are the rotation offsets of the rho step, indexed by x+5y.
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccakPi maps every lane, indexed by x+5y, to its position after the pi
+// step, y+5(2x+3y).
+var keccakPi = func() (pi [25]int) {
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			pi[x+5*y] = y + 5*((2*x+3*y)%5)
+		}
+	}
+	return pi
+}()
+
+// keccakF1600 applies the Keccak-f[1600] permutation to the state, lane by
+// lane, as written in the specification.
+func keccakF1600(a *[25]uint64) {
+	var (
+		b [25]uint64
+		c [5]uint64
+	)
+	for round := 0; round < 24; round++ {
+		// Theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			a[x] ^= d
+			a[x+5] ^= d
+			a[x+10] ^= d
+			a[x+15] ^= d
+			a[x+20] ^= d
+		}
+		// Rho and pi
+		for i := 0; i < 25; i++ {
+			b[keccakPi[i]] = bits.RotateLeft64(a[i], keccakRotations[i])
+		}
+		// Chi
+		for y := 0; y < 25; y += 5 {
+			b0, b1, b2, b3, b4 := b[y], b[y+1], b[y+2], b[y+3], b[y+4]
+			a[y] = b0 ^ (^b1 & b2)
+			a[y+1] = b1 ^ (^b2 & b3)
+			a[y+2] = b2 ^ (^b3 & b4)
+			a[y+3] = b3 ^ (^b4 & b0)
+			a[y+4] = b4 ^ (^b0 & b1)
+		}
+		// Iota
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
+
+// portableKeccakState is a Keccak256 sponge in plain Go, without assembly or
+// platform specific code. It is the fallback backend for platforms where the
+// others are unavailable or misbehave.
+type portableKeccakState struct {
+	a         [25]uint64
+	buf       [keccak256Rate]byte // Input absorbed next, or output squeezed so far
+	n         int                 // Bytes of buf filled while absorbing, or consumed while squeezing
+	squeezing bool
+}
+
+func newPortableKeccakState() KeccakState {
+	return new(portableKeccakState)
+}
+
+// permute XORs the full buffer into the state and applies the permutation.
+func (s *portableKeccakState) permute() {
+	for i := 0; i < keccak256Rate/8; i++ {
+		s.a[i] ^= binary.LittleEndian.Uint64(s.buf[i*8:])
+	}
+	keccakF1600(&s.a)
+}
+
+// squeeze fills the buffer with the next block of output.
+func (s *portableKeccakState) squeeze() {
+	for i := 0; i < keccak256Rate/8; i++ {
+		binary.LittleEndian.PutUint64(s.buf[i*8:], s.a[i])
+	}
+	s.n = 0
+}
+
+func (s *portableKeccakState) Write(p []byte) (int, error) {
+	if s.squeezing {
+		panic("keccak: Write after Read")
+	}
+	written := len(p)
+	for len(p) > 0 {
+		c := copy(s.buf[s.n:], p)
+		s.n += c
+		p = p[c:]
+		if s.n == keccak256Rate {
+			s.permute()
+			s.n = 0
+		}
+	}
+	return written, nil
+}
+
+func (s *portableKeccakState) Read(p []byte) (int, error) {
+	if !s.squeezing {
+		// Legacy Keccak padding: a 0x01 byte after the input and a final
+		// 0x80 bit, merging into 0x81 if only one byte is left.
+		clear(s.buf[s.n:])
+		s.buf[s.n] ^= 0x01
+		s.buf[keccak256Rate-1] ^= 0x80
+		s.permute()
+		s.squeeze()
+		s.squeezing = true
+	}
+	read := len(p)
+	for len(p) > 0 {
+		if s.n == keccak256Rate {
+			keccakF1600(&s.a)
+			s.squeeze()
+		}
+		c := copy(p, s.buf[s.n:])
+		s.n += c
+		p = p[c:]
+	}
+	return read, nil
+}
+
+func (s *portableKeccakState) Sum(b []byte) []byte {
+	if s.squeezing {
+		panic("keccak: Sum after Read")
+	}
+	dup := *s
+	var h [32]byte
+	dup.Read(h[:])
+	return append(b, h[:]...)
+}
+
+func (s *portableKeccakState) Reset() {
+	*s = portableKeccakState{}
+}
+
+func (s *portableKeccakState) Clone() KeccakState {
+	clone := *s
+	return &clone
+}
+
+func (s *portableKeccakState) Size() int {
+	return 32
+}
+
+func (s *portableKeccakState) BlockSize() int {
+	return keccak256Rate
+}
@@ -20,7 +20,6 @@ package crypto
 
 import (
 	"github.com/ProjectZKM/Ziren/crates/go-runtime/zkvm_runtime"
-	"github.com/ethereum/go-ethereum/common"
 )
 
 // zirenKeccakState implements the KeccakState interface using the Ziren zkvm_runtime.
@@ -54,6 +53,15 @@ func (s *zirenKeccakState) Reset() {
 	s.dirty = false
 }
 
+func (s *zirenKeccakState) Clone() KeccakState {
+	// The cached result is replaced rather than modified, so it can be shared.
+	return &zirenKeccakState{
+		buf:    append(make([]byte, 0, cap(s.buf)), s.buf...),
+		result: s.result,
+		dirty:  s.dirty,
+	}
+}
+
 func (s *zirenKeccakState) Size() int {
 	return 32
 }
@@ -83,24 +91,27 @@ func (s *zirenKeccakState) computeHashIfNeeded() {
 	}
 }
 
-// NewKeccakState creates a new KeccakState
-// This uses a Ziren-optimized implementation that leverages the zkvm_runtime.Keccak256 system call.
-func NewKeccakState() KeccakState {
-	return newZirenKeccakState()
-}
+// zirenKeccak hashes with the zkvm_runtime.Keccak256 system call. One-shot
+// hashes are passed to the system call directly, without a pooled state.
+var zirenKeccak = newKeccakBackend(KeccakZiren, newZirenKeccakState, true)
 
-// Keccak256 calculates and returns the Keccak256 hash using the Ziren zkvm_runtime implementation.
-func Keccak256(data ...[]byte) []byte {
-	// For multiple data chunks, concatenate them
-	if len(data) == 0 {
+// platformKeccakBackends are the Keccak256 backends specific to the build
+// target, preferred over the portable ones.
+var platformKeccakBackends = []*keccakBackend{zirenKeccak}
+
+// directKeccak256 writes the Keccak256 hash of the input data into dst using
+// the Ziren zkvm_runtime implementation.
+func directKeccak256(dst []byte, data [][]byte) {
+	switch len(data) {
+	case 0:
 		result := zkvm_runtime.Keccak256(nil)
-		return result[:]
-	}
-	if len(data) == 1 {
+		copy(dst, result[:])
+		return
+	case 1:
 		result := zkvm_runtime.Keccak256(data[0])
-		return result[:]
+		copy(dst, result[:])
+		return
 	}
-
 	// Concatenate multiple data chunks
 	var totalLen int
 	for _, d := range data {
@@ -113,10 +124,5 @@ func Keccak256(data ...[]byte) []byte {
 	}
 
 	result := zkvm_runtime.Keccak256(combined)
-	return result[:]
-}
-
-// Keccak256Hash calculates and returns the Keccak256 hash as a Hash using the Ziren zkvm_runtime implementation.
-func Keccak256Hash(data ...[]byte) common.Hash {
-	return common.Hash(Keccak256(data...))
+	copy(dst, result[:])
 }
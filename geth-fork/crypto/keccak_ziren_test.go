@@ -190,6 +190,31 @@ func TestKeccakStateReset(t *testing.T) {
         }
 }
 
+// TestKeccakStateClone tests that clones of a state finalize independently
+func TestKeccakStateClone(t *testing.T) {
+        state := NewKeccakState()
+        state.Write([]byte("hello "))
+
+        // Branch the hash after the common prefix
+        clone, err := CloneKeccakState(state)
+        if err != nil {
+                t.Fatalf("failed to clone state: %v", err)
+        }
+        state.Write([]byte("world"))
+        clone.Write([]byte("there"))
+
+        result1 := make([]byte, 32)
+        state.Read(result1)
+        if expected := Keccak256([]byte("hello world")); !bytes.Equal(result1, expected) {
+                t.Errorf("original hash mismatch: got=%x, want=%x", result1, expected)
+        }
+        result2 := make([]byte, 32)
+        clone.Read(result2)
+        if expected := Keccak256([]byte("hello there")); !bytes.Equal(result2, expected) {
+                t.Errorf("clone hash mismatch: got=%x, want=%x", result2, expected)
+        }
+}
+
 // TestKeccakStateSize tests Size() and BlockSize() methods
 func TestKeccakStateSize(t *testing.T) {
         state := NewKeccakState()
@@ -0,0 +1,118 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Prefixes of the hashed leaf and parent nodes of Merkle trees.
+var (
+	merkleLeafPrefix = []byte{0x00}
+	merkleNodePrefix = []byte{0x01}
+)
+
+// MerkleRoot returns the root of the binary Merkle tree over the given leaves.
+// Every leaf node is the Keccak256 of a 0x00 byte followed by its leaf, and
+// every parent node is the Keccak256 of a 0x01 byte followed by its left and
+// right child concatenated. A level with an odd number of nodes pairs its last
+// node with itself. The root of no leaves is the zero hash.
+//
+// The prefixes keep leaf and parent nodes apart, so that no parent node can be
+// passed off as a leaf with a shortened proof. Duplicating the last node means
+// that a list of leaves and the same list with its last leaf repeated share a
+// root, so the root commits to the leaves but not to their count.
+func MerkleRoot(leaves [][]byte) common.Hash {
+	if len(leaves) == 0 {
+		return common.Hash{}
+	}
+	level := merkleLeaves(leaves)
+	for len(level) > 1 {
+		level = merkleParents(level)
+	}
+	return level[0]
+}
+
+// MerkleProof returns the proof that the leaf at the given index is part of the
+// Merkle tree over the leaves, as computed by MerkleRoot. The proof lists the
+// sibling nodes from the leaf up to the root.
+func MerkleProof(leaves [][]byte, index int) ([][]byte, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("leaf index %d out of range for %d leaves", index, len(leaves))
+	}
+	var proof [][]byte
+	for level := merkleLeaves(leaves); len(level) > 1; level = merkleParents(level) {
+		sibling := index ^ 1
+		if sibling == len(level) {
+			sibling = index
+		}
+		node := level[sibling]
+		proof = append(proof, node[:])
+		index >>= 1
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof reports whether the leaf is at the given index of the
+// Merkle tree with the given root, as computed by MerkleRoot. The leaf is hashed
+// into its leaf node here, and the proof lists the sibling nodes from the leaf
+// up to the root. The bits of the index, from least significant upward, tell
+// whether the node at each level is the left (0) or right (1) child.
+func VerifyMerkleProof(root common.Hash, leaf []byte, proof [][]byte, index int) bool {
+	if index < 0 || len(proof) < 64 && index>>len(proof) != 0 {
+		return false
+	}
+	var node common.Hash
+	Keccak256Into((*[32]byte)(&node), merkleLeafPrefix, leaf)
+	for _, sibling := range proof {
+		if len(sibling) != common.HashLength {
+			return false
+		}
+		if index&1 == 0 {
+			Keccak256Into((*[32]byte)(&node), merkleNodePrefix, node[:], sibling)
+		} else {
+			Keccak256Into((*[32]byte)(&node), merkleNodePrefix, sibling, node[:])
+		}
+		index >>= 1
+	}
+	return node == root
+}
+
+// merkleLeaves returns the leaf nodes of the Merkle tree over the leaves.
+func merkleLeaves(leaves [][]byte) []common.Hash {
+	nodes := make([]common.Hash, len(leaves))
+	for i, leaf := range leaves {
+		Keccak256Into((*[32]byte)(&nodes[i]), merkleLeafPrefix, leaf)
+	}
+	return nodes
+}
+
+// merkleParents hashes the nodes of a Merkle tree level pairwise into the level
+// above, in place, and returns it. The last node of an odd level is paired with
+// itself.
+func merkleParents(level []common.Hash) []common.Hash {
+	for i := 0; i < len(level); i += 2 {
+		right := level[i]
+		if i+1 < len(level) {
+			right = level[i+1]
+		}
+		Keccak256Into((*[32]byte)(&level[i/2]), merkleNodePrefix, level[i][:], right[:])
+	}
+	return level[:(len(level)+1)/2]
+}
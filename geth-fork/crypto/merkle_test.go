@@ -0,0 +1,105 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+	ha, hb, hc := Keccak256([]byte{0x00}, a), Keccak256([]byte{0x00}, b), Keccak256([]byte{0x00}, c)
+	node := func(left, right []byte) []byte {
+		return Keccak256([]byte{0x01}, left, right)
+	}
+
+	tests := []struct {
+		leaves [][]byte
+		want   common.Hash
+	}{
+		{nil, common.Hash{}},
+		{[][]byte{a}, common.BytesToHash(ha)},
+		{[][]byte{a, b}, common.BytesToHash(node(ha, hb))},
+		{[][]byte{a, b, c}, common.BytesToHash(node(node(ha, hb), node(hc, hc)))},
+	}
+	for i, tt := range tests {
+		if root := MerkleRoot(tt.leaves); root != tt.want {
+			t.Errorf("test %d: root %x, want %x", i, root, tt.want)
+		}
+	}
+}
+
+func TestMerkleProof(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		leaves := make([][]byte, n)
+		for i := range leaves {
+			leaves[i] = []byte(fmt.Sprintf("leaf %d", i))
+		}
+		root := MerkleRoot(leaves)
+		for i := range leaves {
+			proof, err := MerkleProof(leaves, i)
+			if err != nil {
+				t.Fatalf("%d leaves: failed to prove leaf %d: %v", n, i, err)
+			}
+			leaf := leaves[i]
+			if !VerifyMerkleProof(root, leaf, proof, i) {
+				t.Errorf("%d leaves: proof of leaf %d rejected", n, i)
+			}
+			if i^1 < n && VerifyMerkleProof(root, leaf, proof, i^1) {
+				t.Errorf("%d leaves: proof of leaf %d accepted at index %d", n, i, i^1)
+			}
+			if VerifyMerkleProof(root, []byte("other"), proof, i) {
+				t.Errorf("%d leaves: proof of leaf %d accepted for another leaf", n, i)
+			}
+			if VerifyMerkleProof(root, leaf, proof, i+1<<len(proof)) {
+				t.Errorf("%d leaves: proof of leaf %d accepted with an oversized index", n, i)
+			}
+		}
+		// The proof is a copy, unaffected by the tree computed after it.
+		proof, _ := MerkleProof(leaves, 0)
+		if want := Keccak256([]byte{0x00}, leaves[1%n]); n > 1 && !bytes.Equal(proof[0], want) {
+			t.Errorf("%d leaves: first sibling %x, want %x", n, proof[0], want)
+		}
+	}
+	if _, err := MerkleProof(nil, 0); err == nil {
+		t.Error("expected error for proof over no leaves")
+	}
+	if _, err := MerkleProof([][]byte{{0x01}}, 1); err == nil {
+		t.Error("expected error for out of range index")
+	}
+}
+
+// TestMerkleProofInternalNode tests that a parent node cannot be proven as a
+// leaf with the proof of its children shortened by a level.
+func TestMerkleProofInternalNode(t *testing.T) {
+	leaves := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+	root := MerkleRoot(leaves)
+
+	proof, err := MerkleProof(leaves, 0)
+	if err != nil {
+		t.Fatalf("failed to prove leaf 0: %v", err)
+	}
+	// The parent of the first two leaves is the left child of the root.
+	parent := Keccak256([]byte{0x01}, Keccak256([]byte{0x00}, leaves[0]), proof[0])
+	if VerifyMerkleProof(root, parent, proof[1:], 0) {
+		t.Error("internal node accepted as a leaf")
+	}
+}